@@ -8,6 +8,7 @@ import (
 	"github.com/ellcrys/docker/api/types"
 	"github.com/ellcrys/docker/api/types/backend"
 	"github.com/ellcrys/docker/builder"
+	"github.com/ellcrys/docker/builder/cachemounts"
 	"github.com/ellcrys/docker/builder/fscache"
 	"github.com/ellcrys/docker/image"
 	"github.com/ellcrys/docker/pkg/stringid"
@@ -29,12 +30,13 @@ type Builder interface {
 type Backend struct {
 	builder        Builder
 	fsCache        *fscache.FSCache
+	cacheMounts    *cachemounts.Store
 	imageComponent ImageComponent
 }
 
 // NewBackend creates a new build backend from components
-func NewBackend(components ImageComponent, builder Builder, fsCache *fscache.FSCache) (*Backend, error) {
-	return &Backend{imageComponent: components, builder: builder, fsCache: fsCache}, nil
+func NewBackend(components ImageComponent, builder Builder, fsCache *fscache.FSCache, cacheMounts *cachemounts.Store) (*Backend, error) {
+	return &Backend{imageComponent: components, builder: builder, fsCache: fsCache, cacheMounts: cacheMounts}, nil
 }
 
 // Build builds an image from a Source
@@ -68,12 +70,20 @@ func (b *Backend) Build(ctx context.Context, config backend.BuildConfig) (string
 	return imageID, err
 }
 
-// PruneCache removes all cached build sources
+// PruneCache removes all cached build sources, and the cache mounts which
+// are not in use
 func (b *Backend) PruneCache(ctx context.Context) (*types.BuildCachePruneReport, error) {
 	size, err := b.fsCache.Prune(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to prune build cache")
 	}
+	if b.cacheMounts != nil {
+		mountsSize, err := b.cacheMounts.Prune(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to prune build cache mounts")
+		}
+		size += mountsSize
+	}
 	return &types.BuildCachePruneReport{SpaceReclaimed: size}, nil
 }
 
@@ -140,6 +140,8 @@ func (s *containerRouter) getContainersLogs(ctx context.Context, w http.Response
 		ShowStdout: stdout,
 		ShowStderr: stderr,
 		Details:    httputils.BoolValue(r, "details"),
+		Pattern:    r.Form.Get("pattern"),
+		Regex:      httputils.BoolValue(r, "regex"),
 	}
 
 	msgs, tty, err := s.backend.ContainerLogs(ctx, containerName, logsConfig)
@@ -149,7 +149,7 @@ func (s *imageRouter) getImagesGet(ctx context.Context, w http.ResponseWriter, r
 		names = r.Form["names"]
 	}
 
-	if err := s.backend.ExportImage(names, output); err != nil {
+	if err := s.backend.ExportImage(names, r.Form.Get("format"), output); err != nil {
 		if !output.Flushed() {
 			return err
 		}
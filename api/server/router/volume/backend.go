@@ -2,6 +2,7 @@ package volume // import "github.com/ellcrys/docker/api/server/router/volume"
 
 import (
 	"context"
+	"io"
 
 	// TODO return types need to be refactored into pkg
 	"github.com/ellcrys/docker/api/types"
@@ -11,9 +12,13 @@ import (
 // Backend is the methods that need to be implemented to provide
 // volume specific functionality
 type Backend interface {
-	Volumes(filter string) ([]*types.Volume, []string, error)
+	Volumes(filter, sortBy string) ([]*types.Volume, []string, error)
 	VolumeInspect(name string) (*types.Volume, error)
 	VolumeCreate(name, driverName string, opts, labels map[string]string) (*types.Volume, error)
+	VolumeClone(source, name, driverName string, labels map[string]string) (*types.Volume, error)
+	VolumeSnapshot(source, name string, labels map[string]string) (*types.Volume, error)
+	VolumeExport(name, compression string, out io.Writer) error
+	VolumeImport(name, driverName string, labels map[string]string, in io.Reader) (*types.Volume, error)
 	VolumeRm(name string, force bool) error
 	VolumesPrune(ctx context.Context, pruneFilters filters.Args) (*types.VolumesPruneReport, error)
 }
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/ellcrys/docker/api/server/httputils"
+	"github.com/ellcrys/docker/api/types"
 	"github.com/ellcrys/docker/api/types/filters"
 	volumetypes "github.com/ellcrys/docker/api/types/volume"
 	"github.com/ellcrys/docker/errdefs"
@@ -18,7 +19,7 @@ func (v *volumeRouter) getVolumesList(ctx context.Context, w http.ResponseWriter
 		return err
 	}
 
-	volumes, warnings, err := v.backend.Volumes(r.Form.Get("filters"))
+	volumes, warnings, err := v.backend.Volumes(r.Form.Get("filters"), r.Form.Get("sort"))
 	if err != nil {
 		return err
 	}
@@ -54,7 +55,69 @@ func (v *volumeRouter) postVolumesCreate(ctx context.Context, w http.ResponseWri
 		return err
 	}
 
-	volume, err := v.backend.VolumeCreate(req.Name, req.Driver, req.DriverOpts, req.Labels)
+	var (
+		volume *types.Volume
+		err    error
+	)
+	if req.From != "" {
+		if len(req.DriverOpts) > 0 {
+			return errdefs.InvalidParameter(errors.New("driver options are not supported when creating a volume from another volume"))
+		}
+		volume, err = v.backend.VolumeClone(req.From, req.Name, req.Driver, req.Labels)
+	} else {
+		volume, err = v.backend.VolumeCreate(req.Name, req.Driver, req.DriverOpts, req.Labels)
+	}
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusCreated, volume)
+}
+
+func (v *volumeRouter) postVolumeSnapshot(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	if err := httputils.CheckForJSON(r); err != nil {
+		return err
+	}
+
+	var req volumetypes.VolumeSnapshotBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if err == io.EOF {
+			return errdefs.InvalidParameter(errors.New("got EOF while reading request body"))
+		}
+		return err
+	}
+
+	volume, err := v.backend.VolumeSnapshot(vars["name"], req.Name, req.Labels)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusCreated, volume)
+}
+
+func (v *volumeRouter) getVolumeExport(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	return v.backend.VolumeExport(vars["name"], r.Form.Get("compression"), w)
+}
+
+func (v *volumeRouter) postVolumesImport(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	var labels map[string]string
+	if labelsJSON := r.Form.Get("labels"); labelsJSON != "" {
+		if err := json.Unmarshal([]byte(labelsJSON), &labels); err != nil {
+			return errdefs.InvalidParameter(errors.New("labels must be a JSON object of strings"))
+		}
+	}
+
+	volume, err := v.backend.VolumeImport(r.Form.Get("name"), r.Form.Get("driver"), labels, r.Body)
 	if err != nil {
 		return err
 	}
@@ -79,6 +79,11 @@ type ContainerLogsOptions struct {
 	Follow     bool
 	Tail       string
 	Details    bool
+
+	// Pattern only returns the log lines containing it, or matching it if
+	// Regex is set. The daemon applies it before Tail.
+	Pattern string
+	Regex   bool
 }
 
 // ContainerRemoveOptions holds parameters to remove containers.
@@ -211,6 +216,18 @@ type ImageImportOptions struct {
 	Platform string   // Platform is the target platform of the image
 }
 
+// VolumeExportOptions holds parameters to export the content of a volume.
+type VolumeExportOptions struct {
+	Compression string // Compression is the compression of the archive: none (the default), gzip or zstd
+}
+
+// VolumeImportOptions holds parameters to import the content of a volume.
+type VolumeImportOptions struct {
+	Name   string            // Name is the name of the volume, created if it does not exist
+	Driver string            // Driver is the driver of the volume created
+	Labels map[string]string // Labels are the labels of the volume created
+}
+
 // ImageListOptions holds parameters to filter the list of images with.
 type ImageListOptions struct {
 	All     bool
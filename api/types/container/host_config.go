@@ -2,6 +2,7 @@ package container // import "github.com/ellcrys/docker/api/types/container"
 
 import (
 	"strings"
+	"time"
 
 	"github.com/ellcrys/docker/api/types/blkiodev"
 	"github.com/ellcrys/docker/api/types/mount"
@@ -255,6 +256,17 @@ type DeviceMapping struct {
 type RestartPolicy struct {
 	Name              string
 	MaximumRetryCount int
+
+	// Backoff between restarts. Zero means use the default. Durations are
+	// expressed as integer nanoseconds.
+	InitialBackoff     time.Duration `json:",omitempty"` // InitialBackoff is the delay before the first restart.
+	MaxBackoff         time.Duration `json:",omitempty"` // MaxBackoff caps the doubling delay between restarts.
+	BackoffResetWindow time.Duration `json:",omitempty"` // BackoffResetWindow is how long the container must run for the delay to be reset.
+
+	// UnhealthyRetries is the number of consecutive failing healthchecks
+	// after which a container with the "on-unhealthy" policy is restarted.
+	// Zero means restart as soon as the container is unhealthy.
+	UnhealthyRetries int `json:",omitempty"`
 }
 
 // IsNone indicates whether the container has the "no" restart policy.
@@ -275,6 +287,13 @@ func (rp *RestartPolicy) IsOnFailure() bool {
 	return rp.Name == "on-failure"
 }
 
+// IsOnUnhealthy indicates whether the container has the "on-unhealthy"
+// restart policy. This means the container will automatically restart when
+// it becomes unhealthy, or when exiting with a non-zero exit status.
+func (rp *RestartPolicy) IsOnUnhealthy() bool {
+	return rp.Name == "on-unhealthy"
+}
+
 // IsUnlessStopped indicates whether the container has the
 // "unless-stopped" restart policy. This means the container will
 // automatically restart unless user has put it to stopped state.
@@ -284,7 +303,12 @@ func (rp *RestartPolicy) IsUnlessStopped() bool {
 
 // IsSame compares two RestartPolicy to see if they are the same
 func (rp *RestartPolicy) IsSame(tp *RestartPolicy) bool {
-	return rp.Name == tp.Name && rp.MaximumRetryCount == tp.MaximumRetryCount
+	return rp.Name == tp.Name &&
+		rp.MaximumRetryCount == tp.MaximumRetryCount &&
+		rp.InitialBackoff == tp.InitialBackoff &&
+		rp.MaxBackoff == tp.MaxBackoff &&
+		rp.BackoffResetWindow == tp.BackoffResetWindow &&
+		rp.UnhealthyRetries == tp.UnhealthyRetries
 }
 
 // LogMode is a type to define the available modes for logging
@@ -23,6 +23,9 @@ type ImageSummary struct {
 	// Required: true
 	Labels map[string]string `json:"Labels"`
 
+	// last used
+	LastUsed int64 `json:"LastUsed,omitempty"`
+
 	// parent Id
 	// Required: true
 	ParentID string `json:"ParentId"`
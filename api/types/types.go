@@ -50,7 +50,8 @@ type ImageInspect struct {
 
 // ImageMetadata contains engine-local data about the image
 type ImageMetadata struct {
-	LastTagTime time.Time `json:",omitempty"`
+	LastTagTime  time.Time `json:",omitempty"`
+	LastUsedTime time.Time `json:",omitempty"`
 }
 
 // Container contains response of Engine API:
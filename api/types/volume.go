@@ -48,11 +48,32 @@ type Volume struct {
 }
 
 // VolumeUsageData Usage details about the volume. This information is used by the
-// `GET /system/df` endpoint, and omitted in other endpoints.
+// `GET /system/df` endpoint. It is also returned by `GET /volumes` and
+// `GET /volumes/{name}` for the volumes which usage was measured by the
+// daemon, which measures the volumes of the `"local"` volume driver
+// periodically and when they are unmounted. It is omitted in other
+// endpoints.
 //
 // swagger:model VolumeUsageData
 type VolumeUsageData struct {
 
+	// Number of inodes used by the volume. This information is only
+	// available for volumes created with the `"local"` volume driver
+	// without the `size` option, and omitted otherwise.
+	//
+	Inodes int64 `json:"Inodes,omitempty"`
+
+	// Date/Time the volume was last mounted, if the volume was mounted
+	// since the daemon tracks mounts.
+	//
+	LastMounted string `json:"LastMounted,omitempty"`
+
+	// Maximum amount of disk space the volume can use (in bytes). This
+	// information is only available for volumes created with the `size`
+	// option of the `"local"` volume driver, and omitted otherwise.
+	//
+	Limit int64 `json:"Limit,omitempty"`
+
 	// The number of containers referencing this volume. This field
 	// is set to `-1` if the reference-count is not available.
 	//
@@ -19,6 +19,10 @@ type VolumeCreateBody struct {
 	// Required: true
 	DriverOpts map[string]string `json:"DriverOpts"`
 
+	// Name of an existing volume to create the volume as a copy of, made by the volume driver. The volume is created with the driver of the existing volume.
+	// Required: true
+	From string `json:"From,omitempty"`
+
 	// User-defined key/value metadata.
 	// Required: true
 	Labels map[string]string `json:"Labels"`
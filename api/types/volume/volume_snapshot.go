@@ -0,0 +1,21 @@
+package volume
+
+// ----------------------------------------------------------------------------
+// DO NOT EDIT THIS FILE
+// This file was generated by `swagger generate operation`
+//
+// See hack/generate-swagger-api.sh
+// ----------------------------------------------------------------------------
+
+// VolumeSnapshotBody
+// swagger:model VolumeSnapshotBody
+type VolumeSnapshotBody struct {
+
+	// User-defined key/value metadata of the snapshot.
+	// Required: true
+	Labels map[string]string `json:"Labels"`
+
+	// The name of the snapshot. If not specified, Docker generates a name.
+	// Required: true
+	Name string `json:"Name"`
+}
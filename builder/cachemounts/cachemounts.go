@@ -0,0 +1,98 @@
+// Package cachemounts keeps the directories mounted by the
+// RUN --mount=type=cache instructions of the builder across builds.
+package cachemounts // import "github.com/ellcrys/docker/builder/cachemounts"
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ellcrys/docker/pkg/directory"
+	"github.com/ellcrys/docker/pkg/idtools"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Store keeps the cache mounts in a directory, one per cache id.
+type Store struct {
+	root    string
+	rootIDs idtools.IDPair
+
+	mu   sync.Mutex
+	refs map[string]int
+}
+
+// NewStore returns a Store keeping the cache mounts in root. The cache
+// mounts are owned by rootIDs, the root user of the build containers.
+func NewStore(root string, rootIDs idtools.IDPair) (*Store, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, errors.Wrap(err, "failed to create cache mounts directory")
+	}
+	return &Store{
+		root:    root,
+		rootIDs: rootIDs,
+		refs:    make(map[string]int),
+	}, nil
+}
+
+// Get returns the directory of the cache mount with the given id, creating it
+// if it does not exist. The directory is not pruned until release is called.
+// The same directory may be used by several builds at the same time.
+func (s *Store) Get(id string) (dir string, release func(), err error) {
+	name := digest.FromString(id).Hex()
+	dir = filepath.Join(s.root, name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := idtools.MkdirAllAndChownNew(dir, 0755, s.rootIDs); err != nil {
+		return "", nil, errors.Wrapf(err, "failed to create cache mount %s", id)
+	}
+	s.refs[name]++
+
+	var once sync.Once
+	return dir, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.refs[name]--; s.refs[name] == 0 {
+				delete(s.refs, name)
+			}
+		})
+	}, nil
+}
+
+// Prune removes the cache mounts which are not in use, and returns the size
+// of the data removed.
+func (s *Store) Prune(ctx context.Context) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fis, err := ioutil.ReadDir(s.root)
+	if err != nil {
+		return 0, err
+	}
+	var reclaimed uint64
+	for _, fi := range fis {
+		select {
+		case <-ctx.Done():
+			return reclaimed, ctx.Err()
+		default:
+		}
+		if s.refs[fi.Name()] > 0 {
+			continue
+		}
+		dir := filepath.Join(s.root, fi.Name())
+		size, err := directory.Size(ctx, dir)
+		if err != nil {
+			logrus.WithError(err).Warnf("failed to compute the size of cache mount %s", dir)
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return reclaimed, errors.Wrapf(err, "failed to remove cache mount %s", dir)
+		}
+		reclaimed += uint64(size)
+	}
+	return reclaimed, nil
+}
@@ -0,0 +1,55 @@
+package cachemounts // import "github.com/ellcrys/docker/builder/cachemounts"
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ellcrys/docker/pkg/idtools"
+	"github.com/gotestyourself/gotestyourself/assert"
+	is "github.com/gotestyourself/gotestyourself/assert/cmp"
+)
+
+func TestStore(t *testing.T) {
+	root, err := ioutil.TempDir("", "cache-mounts")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+
+	s, err := NewStore(root, idtools.IDPair{UID: os.Getuid(), GID: os.Getgid()})
+	assert.NilError(t, err)
+
+	dir1, release1, err := s.Get("/root/.cache")
+	assert.NilError(t, err)
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir1, "foo"), []byte("foo"), 0644))
+
+	dir2, release2, err := s.Get("/root/.cache")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(dir1, dir2))
+
+	other, releaseOther, err := s.Get("other")
+	assert.NilError(t, err)
+	assert.Check(t, dir1 != other)
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(other, "bar"), []byte("barbar"), 0644))
+	releaseOther()
+
+	reclaimed, err := s.Prune(context.Background())
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(reclaimed, uint64(6)))
+	_, err = os.Stat(filepath.Join(dir1, "foo"))
+	assert.Check(t, err, "expected a cache mount in use not to be pruned")
+
+	release1()
+	release1()
+	reclaimed, err = s.Prune(context.Background())
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(reclaimed, uint64(0)))
+
+	release2()
+	reclaimed, err = s.Prune(context.Background())
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(reclaimed, uint64(3)))
+	_, err = os.Stat(dir1)
+	assert.Check(t, os.IsNotExist(err))
+}
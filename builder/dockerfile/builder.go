@@ -14,6 +14,7 @@ import (
 	"github.com/ellcrys/docker/api/types/backend"
 	"github.com/ellcrys/docker/api/types/container"
 	"github.com/ellcrys/docker/builder"
+	"github.com/ellcrys/docker/builder/cachemounts"
 	"github.com/ellcrys/docker/builder/dockerfile/instructions"
 	"github.com/ellcrys/docker/builder/dockerfile/parser"
 	"github.com/ellcrys/docker/builder/dockerfile/shell"
@@ -57,18 +58,20 @@ type BuildManager struct {
 	idMappings *idtools.IDMappings
 	backend    builder.Backend
 	pathCache  pathCache // TODO: make this persistent
-	sg         SessionGetter
-	fsCache    *fscache.FSCache
+	sg          SessionGetter
+	fsCache     *fscache.FSCache
+	cacheMounts *cachemounts.Store
 }
 
 // NewBuildManager creates a BuildManager
-func NewBuildManager(b builder.Backend, sg SessionGetter, fsCache *fscache.FSCache, idMappings *idtools.IDMappings) (*BuildManager, error) {
+func NewBuildManager(b builder.Backend, sg SessionGetter, fsCache *fscache.FSCache, cacheMounts *cachemounts.Store, idMappings *idtools.IDMappings) (*BuildManager, error) {
 	bm := &BuildManager{
-		backend:    b,
-		pathCache:  &syncmap.Map{},
-		sg:         sg,
-		idMappings: idMappings,
-		fsCache:    fsCache,
+		backend:     b,
+		pathCache:   &syncmap.Map{},
+		sg:          sg,
+		idMappings:  idMappings,
+		fsCache:     fsCache,
+		cacheMounts: cacheMounts,
 	}
 	if err := fsCache.RegisterTransport(remotecontext.ClientSessionRemote, NewClientSessionTransport()); err != nil {
 		return nil, err
@@ -117,6 +120,8 @@ func (bm *BuildManager) Build(ctx context.Context, config backend.BuildConfig) (
 		Backend:        bm.backend,
 		PathCache:      bm.pathCache,
 		IDMappings:     bm.idMappings,
+		SessionGetter:  bm.sg,
+		CacheMounts:    bm.cacheMounts,
 	}
 	return newBuilder(ctx, builderOptions).build(source, dockerfile)
 }
@@ -161,6 +166,8 @@ type builderOptions struct {
 	ProgressWriter backend.ProgressWriter
 	PathCache      pathCache
 	IDMappings     *idtools.IDMappings
+	SessionGetter  SessionGetter
+	CacheMounts    *cachemounts.Store
 }
 
 // Builder is a Dockerfile builder
@@ -182,6 +189,8 @@ type Builder struct {
 	pathCache        pathCache
 	containerManager *containerManager
 	imageProber      ImageProber
+	sessionGetter    SessionGetter
+	cacheMounts      *cachemounts.Store
 }
 
 // newBuilder creates a new Dockerfile builder from an optional dockerfile and a Options.
@@ -204,6 +213,8 @@ func newBuilder(clientCtx context.Context, options builderOptions) *Builder {
 		pathCache:        options.PathCache,
 		imageProber:      newImageProber(options.Backend, config.CacheFrom, config.NoCache),
 		containerManager: newContainerManager(options.Backend),
+		sessionGetter:    options.SessionGetter,
+		cacheMounts:      options.CacheMounts,
 	}
 
 	return b
@@ -4,6 +4,7 @@ import (
 	"archive/tar"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"mime"
 	"net/http"
 	"net/url"
@@ -15,6 +16,7 @@ import (
 	"time"
 
 	"github.com/ellcrys/docker/builder"
+	"github.com/ellcrys/docker/builder/dockerfile/parser"
 	"github.com/ellcrys/docker/builder/remotecontext"
 	"github.com/ellcrys/docker/pkg/archive"
 	"github.com/ellcrys/docker/pkg/containerfs"
@@ -72,6 +74,7 @@ type copier struct {
 	source      builder.Source
 	pathCache   pathCache
 	download    sourceDownloader
+	heredoc     func(string) (parser.Heredoc, bool)
 	platform    string
 	// for cleanup. TODO: having copier.cleanup() is error prone and hard to
 	// follow. Code calling performCopy should manage the lifecycle of its params.
@@ -128,6 +131,11 @@ func (o *copier) getCopyInfosForSourcePaths(sources []string, dest string) ([]co
 }
 
 func (o *copier) getCopyInfoForSourcePath(orig, dest string) ([]copyInfo, error) {
+	if o.heredoc != nil {
+		if h, ok := o.heredoc(orig); ok {
+			return o.getCopyInfoForHeredoc(h)
+		}
+	}
 	if !urlutil.IsURL(orig) {
 		return o.calcCopyInfo(orig, true)
 	}
@@ -152,6 +160,33 @@ func (o *copier) getCopyInfoForSourcePath(orig, dest string) ([]copyInfo, error)
 	return newCopyInfos(ci), err
 }
 
+// getCopyInfoForHeredoc writes the content of the heredoc to a file named
+// after it, in a temporary directory, and returns its info.
+func (o *copier) getCopyInfoForHeredoc(h parser.Heredoc) ([]copyInfo, error) {
+	tmpDir, err := ioutils.TempDir("", "docker-heredoc")
+	if err != nil {
+		return nil, err
+	}
+	o.tmpPaths = append(o.tmpPaths, tmpDir)
+
+	p := filepath.Join(tmpDir, h.Name)
+	if err := ioutil.WriteFile(p, []byte(h.Content), 0644); err != nil {
+		return nil, err
+	}
+	if err := system.Chtimes(p, time.Time{}, time.Time{}); err != nil {
+		return nil, err
+	}
+
+	source, err := remotecontext.NewLazySource(containerfs.NewLocalContainerFS(tmpDir))
+	if err != nil {
+		return nil, err
+	}
+	hash, err := source.Hash(h.Name)
+	ci := newCopyInfoFromSource(source, h.Name, hash)
+	ci.noDecompress = true
+	return newCopyInfos(ci), err
+}
+
 // Cleanup removes any temporary directories created as part of downloading
 // remote files.
 func (o *copier) Cleanup() {
@@ -120,6 +120,7 @@ func dispatchCopy(d dispatchRequest, c *instructions.CopyCommand) error {
 		}
 	}
 	copier := copierFromDispatchRequest(d, errOnSourceDownload, im)
+	copier.heredoc = c.Heredoc
 	defer copier.Cleanup()
 	copyInstruction, err := copier.createCopyInstruction(c.SourcesAndDest, "COPY")
 	if err != nil {
@@ -346,6 +347,9 @@ func dispatchRun(d dispatchRequest, c *instructions.RunCommand) error {
 	if len(buildArgs) > 0 {
 		saveCmd = prependEnvOnCmd(d.state.buildArgs, buildArgs, cmdFromArgs)
 	}
+	if len(c.Mounts) > 0 {
+		saveCmd = prependMountsOnCmd(c.Mounts, saveCmd)
+	}
 
 	runConfigForCacheProbe := copyRunConfig(stateRunConfig,
 		withCmd(saveCmd),
@@ -363,8 +367,14 @@ func dispatchRun(d dispatchRequest, c *instructions.RunCommand) error {
 	// set config as already being escaped, this prevents double escaping on windows
 	runConfig.ArgsEscaped = true
 
+	mounts, releaseMounts, err := d.builder.runMounts(c.Mounts)
+	if err != nil {
+		return err
+	}
+	defer releaseMounts()
+
 	logrus.Debugf("[BUILDER] Command to be executed: %v", runConfig.Cmd)
-	cID, err := d.builder.create(runConfig)
+	cID, err := d.builder.create(runConfig, mounts...)
 	if err != nil {
 		return err
 	}
@@ -53,6 +53,14 @@ func dispatch(d dispatchRequest, cmd instructions.Command) (err error) {
 			return errdefs.InvalidParameter(err)
 		}
 	}
+	if ex, ok := cmd.(instructions.SupportsHeredocExpansion); ok {
+		err := ex.ExpandHeredocs(func(content string) (string, error) {
+			return d.shlex.ProcessHeredoc(content, envs)
+		})
+		if err != nil {
+			return errdefs.InvalidParameter(err)
+		}
+	}
 
 	defer func() {
 		if d.builder.options.ForceRemove {
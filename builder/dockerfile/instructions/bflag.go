@@ -11,6 +11,7 @@ type FlagType int
 const (
 	boolType FlagType = iota
 	stringType
+	stringsType
 )
 
 // BFlags contains all flags information for the builder
@@ -25,8 +26,9 @@ type BFlags struct {
 type Flag struct {
 	bf       *BFlags
 	name     string
-	flagType FlagType
-	Value    string
+	flagType     FlagType
+	Value        string
+	StringValues []string
 }
 
 // NewBFlags returns the new BFlags struct
@@ -70,6 +72,13 @@ func (bf *BFlags) AddString(name string, def string) *Flag {
 	return flag
 }
 
+// AddStrings adds a string flag to BFlags which can be specified multiple
+// times, its values are kept in StringValues.
+// Note, any error will be generated when Parse() is called (see Parse).
+func (bf *BFlags) AddStrings(name string) *Flag {
+	return bf.addFlag(name, stringsType)
+}
+
 // addFlag is a generic func used by the other AddXXX() func
 // to add a new flag to the BFlags struct.
 // Note, any error will be generated when Parse() is called (see Parse).
@@ -145,7 +154,7 @@ func (bf *BFlags) Parse() error {
 			return fmt.Errorf("Unknown flag: %s", arg)
 		}
 
-		if _, ok = bf.used[arg]; ok {
+		if _, ok = bf.used[arg]; ok && flag.flagType != stringsType {
 			return fmt.Errorf("Duplicate flag specified: %s", arg)
 		}
 
@@ -173,6 +182,12 @@ func (bf *BFlags) Parse() error {
 			}
 			flag.Value = value
 
+		case stringsType:
+			if index < 0 {
+				return fmt.Errorf("Missing a value on flag: %s", arg)
+			}
+			flag.StringValues = append(flag.StringValues, value)
+
 		default:
 			panic("No idea what kind of flag we have! Should never get here!")
 		}
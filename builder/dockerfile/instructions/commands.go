@@ -6,6 +6,7 @@ import (
 
 	"github.com/ellcrys/docker/api/types/container"
 	"github.com/ellcrys/docker/api/types/strslice"
+	"github.com/ellcrys/docker/builder/dockerfile/parser"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
@@ -54,6 +55,12 @@ type SupportsSingleWordExpansion interface {
 	Expand(expander SingleWordExpander) error
 }
 
+// SupportsHeredocExpansion interface allows expanding variables in the
+// content of heredocs
+type SupportsHeredocExpansion interface {
+	ExpandHeredocs(expander SingleWordExpander) error
+}
+
 // PlatformSpecific adds platform checks to a command
 type PlatformSpecific interface {
 	CheckPlatform(platform string) error
@@ -181,15 +188,49 @@ func (c *AddCommand) Expand(expander SingleWordExpander) error {
 type CopyCommand struct {
 	withNameAndCode
 	SourcesAndDest
-	From  string
-	Chown string
+	From     string
+	Chown    string
+	Heredocs []parser.Heredoc
 }
 
+// HeredocSourcePrefix prefixes the name of a heredoc in the sources of a
+// COPY, for which a file with the heredoc content is copied.
+const HeredocSourcePrefix = "<<"
+
 // Expand variables
 func (c *CopyCommand) Expand(expander SingleWordExpander) error {
 	return expandSliceInPlace(c.SourcesAndDest, expander)
 }
 
+// ExpandHeredocs expands variables in the content of the heredocs whose
+// delimiter is not quoted.
+func (c *CopyCommand) ExpandHeredocs(expander SingleWordExpander) error {
+	for i, h := range c.Heredocs {
+		if !h.Expand {
+			continue
+		}
+		content, err := expander(h.Content)
+		if err != nil {
+			return err
+		}
+		c.Heredocs[i].Content = content
+	}
+	return nil
+}
+
+// Heredoc returns the heredoc a COPY source refers to, if any.
+func (c *CopyCommand) Heredoc(source string) (parser.Heredoc, bool) {
+	if !strings.HasPrefix(source, HeredocSourcePrefix) {
+		return parser.Heredoc{}, false
+	}
+	for _, h := range c.Heredocs {
+		if h.Name == strings.TrimPrefix(source, HeredocSourcePrefix) {
+			return h, true
+		}
+	}
+	return parser.Heredoc{}, false
+}
+
 // OnbuildCommand : ONBUILD <some other command>
 type OnbuildCommand struct {
 	withNameAndCode
@@ -234,6 +275,19 @@ type ShellDependantCmdLine struct {
 type RunCommand struct {
 	withNameAndCode
 	ShellDependantCmdLine
+	Heredocs []parser.Heredoc
+	Mounts   []*RunMount
+}
+
+// CheckPlatform checks that the command is supported in the target platform
+func (c *RunCommand) CheckPlatform(platform string) error {
+	if platform == "windows" && len(c.Heredocs) > 0 {
+		return errors.New("The daemon on this platform does not support heredocs in RUN")
+	}
+	if platform == "windows" && len(c.Mounts) > 0 {
+		return errors.New("The daemon on this platform does not support RUN --mount")
+	}
+	return nil
 }
 
 // CmdCommand : CMD foo
@@ -0,0 +1,138 @@
+package instructions // import "github.com/ellcrys/docker/builder/dockerfile/instructions"
+
+import (
+	"encoding/csv"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MountType is the type of a RUN --mount
+type MountType string
+
+const (
+	// MountTypeCache is a directory kept by the daemon across builds,
+	// identified by its id
+	MountTypeCache MountType = "cache"
+	// MountTypeSecret is a file supplied by the client through the build
+	// session, which is not kept in the image
+	MountTypeSecret MountType = "secret"
+)
+
+// SecretsDir is the directory secrets are mounted to by default
+const SecretsDir = "/run/secrets"
+
+var validSecretID = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// RunMount is a mount of a RUN instruction, as in:
+//
+//   RUN --mount=type=cache,target=/root/.cache/go-build go build
+//   RUN --mount=type=secret,id=aws,required aws s3 cp s3://bucket/file .
+//
+type RunMount struct {
+	Type     MountType
+	ID       string
+	Target   string
+	ReadOnly bool
+	// Required is whether the build fails if a secret is not supplied by the
+	// client. Secrets which are not required are not mounted when missing.
+	Required bool
+}
+
+// String returns the mount as in the Dockerfile
+func (m *RunMount) String() string {
+	s := "type=" + string(m.Type) + ",id=" + m.ID + ",target=" + m.Target
+	if m.ReadOnly && m.Type != MountTypeSecret {
+		s += ",readonly"
+	}
+	if m.Required {
+		s += ",required"
+	}
+	return s
+}
+
+// parseMount parses the value of a RUN --mount flag
+func parseMount(value string) (*RunMount, error) {
+	fields, err := csv.NewReader(strings.NewReader(value)).Read()
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid mount %q", value)
+	}
+
+	m := &RunMount{}
+	for _, field := range fields {
+		parts := strings.SplitN(field, "=", 2)
+		key := strings.ToLower(parts[0])
+		if len(parts) == 1 {
+			switch key {
+			case "readonly", "ro":
+				m.ReadOnly = true
+			case "required":
+				m.Required = true
+			default:
+				return nil, errors.Errorf("invalid field %q in mount %q, must be a key=value pair", field, value)
+			}
+			continue
+		}
+
+		val := parts[1]
+		switch key {
+		case "type":
+			m.Type = MountType(strings.ToLower(val))
+		case "id":
+			m.ID = val
+		case "target", "dst", "destination":
+			m.Target = val
+		case "readonly", "ro", "required":
+			b, err := strconv.ParseBool(val)
+			if err != nil {
+				return nil, errors.Errorf("invalid value %q for %s in mount %q", val, key, value)
+			}
+			if key == "required" {
+				m.Required = b
+			} else {
+				m.ReadOnly = b
+			}
+		default:
+			return nil, errors.Errorf("unknown field %q in mount %q", key, value)
+		}
+	}
+
+	switch m.Type {
+	case MountTypeCache:
+		if m.Target == "" {
+			return nil, errors.Errorf("mount %q has no target", value)
+		}
+		if m.Required {
+			return nil, errors.Errorf("required is only supported for secret mounts")
+		}
+		if m.ID == "" {
+			m.ID = m.Target
+		}
+	case MountTypeSecret:
+		if m.ID == "" {
+			if m.Target == "" {
+				return nil, errors.Errorf("mount %q has no id or target", value)
+			}
+			m.ID = path.Base(m.Target)
+		}
+		if !validSecretID.MatchString(m.ID) {
+			return nil, errors.Errorf("invalid secret id %q, only [a-zA-Z0-9_.-] are allowed", m.ID)
+		}
+		if m.Target == "" {
+			m.Target = path.Join(SecretsDir, m.ID)
+		}
+		m.ReadOnly = true
+	case "":
+		return nil, errors.Errorf("mount %q has no type", value)
+	default:
+		return nil, errors.Errorf("unsupported mount type %q, must be cache or secret", m.Type)
+	}
+	if !path.IsAbs(m.Target) {
+		return nil, errors.Errorf("mount target %q must be an absolute path", m.Target)
+	}
+	m.Target = path.Clean(m.Target)
+	return m, nil
+}
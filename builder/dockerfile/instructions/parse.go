@@ -22,6 +22,7 @@ type parseRequest struct {
 	attributes map[string]bool
 	flags      *BFlags
 	original   string
+	heredocs   []parser.Heredoc
 }
 
 func nodeArgs(node *parser.Node) []string {
@@ -46,11 +47,17 @@ func newParseRequestFromNode(node *parser.Node) parseRequest {
 		attributes: node.Attributes,
 		original:   node.Original,
 		flags:      NewBFlagsWithArgs(node.Flags),
+		heredocs:   node.Heredocs,
 	}
 }
 
 // ParseInstruction converts an AST to a typed instruction (either a command or a build stage beginning when encountering a `FROM` statement)
 func ParseInstruction(node *parser.Node) (interface{}, error) {
+	for _, h := range node.Heredocs {
+		if !h.Terminated {
+			return nil, errors.Errorf("unterminated heredoc <<%s: no line matching %q was found before the end of the Dockerfile", h.Name, h.Name)
+		}
+	}
 	req := newParseRequestFromNode(node)
 	switch node.Value {
 	case command.Env:
@@ -258,14 +265,43 @@ func parseCopy(req parseRequest) (*CopyCommand, error) {
 	if err := req.flags.Parse(); err != nil {
 		return nil, err
 	}
+	sourcesAndDest, err := parseHeredocSources(req.args, req.heredocs)
+	if err != nil {
+		return nil, err
+	}
 	return &CopyCommand{
-		SourcesAndDest:  SourcesAndDest(req.args),
+		SourcesAndDest:  sourcesAndDest,
 		From:            flFrom.Value,
 		withNameAndCode: newWithNameAndCode(req),
 		Chown:           flChown.Value,
+		Heredocs:        req.heredocs,
 	}, nil
 }
 
+// parseHeredocSources replaces the heredoc markers in the sources of a COPY
+// by "<<NAME", the name the heredoc content is copied from.
+func parseHeredocSources(args []string, heredocs []parser.Heredoc) (SourcesAndDest, error) {
+	sourcesAndDest := SourcesAndDest(args)
+	if len(heredocs) == 0 {
+		return sourcesAndDest, nil
+	}
+	next := 0
+	for i, src := range sourcesAndDest.Sources() {
+		if !strings.HasPrefix(src, "<<") {
+			continue
+		}
+		if next == len(heredocs) {
+			return nil, errors.Errorf("invalid heredoc source %s", src)
+		}
+		sourcesAndDest[i] = HeredocSourcePrefix + heredocs[next].Name
+		next++
+	}
+	if next != len(heredocs) {
+		return nil, errors.Errorf("heredoc <<%s must be a COPY source", heredocs[next].Name)
+	}
+	return sourcesAndDest, nil
+}
+
 func parseFrom(req parseRequest) (*Stage, error) {
 	stageName, err := parseBuildStageName(req.args)
 	if err != nil {
@@ -355,17 +391,49 @@ func parseShellDependentCommand(req parseRequest, emptyAsNil bool) ShellDependan
 }
 
 func parseRun(req parseRequest) (*RunCommand, error) {
-
+	flMounts := req.flags.AddStrings("mount")
 	if err := req.flags.Parse(); err != nil {
 		return nil, err
 	}
+	var mounts []*RunMount
+	for _, value := range flMounts.StringValues {
+		m, err := parseMount(value)
+		if err != nil {
+			return nil, err
+		}
+		mounts = append(mounts, m)
+	}
+	cmdLine := parseShellDependentCommand(req, false)
+	if len(req.heredocs) > 0 {
+		cmdLine.CmdLine = strslice.StrSlice{heredocScript(cmdLine.CmdLine[0], req.heredocs)}
+	}
 	return &RunCommand{
-		ShellDependantCmdLine: parseShellDependentCommand(req, false),
+		ShellDependantCmdLine: cmdLine,
 		withNameAndCode:       newWithNameAndCode(req),
+		Heredocs:              req.heredocs,
+		Mounts:                mounts,
 	}, nil
 
 }
 
+// heredocScript returns the shell script run by a RUN followed by heredocs.
+// A RUN made of a single heredoc runs its content, otherwise the heredocs are
+// passed to the command line as in a shell script, and so expanded by the
+// shell as it runs.
+func heredocScript(cmdLine string, heredocs []parser.Heredoc) string {
+	if fields := strings.Fields(cmdLine); len(heredocs) == 1 && len(fields) == 1 && strings.HasPrefix(fields[0], "<<") {
+		return heredocs[0].Content
+	}
+	var script strings.Builder
+	script.WriteString(cmdLine)
+	for _, h := range heredocs {
+		script.WriteString("\n")
+		script.WriteString(h.Content)
+		script.WriteString(h.Name)
+	}
+	return script.String()
+}
+
 func parseCmd(req parseRequest) (*CmdCommand, error) {
 	if err := req.flags.Parse(); err != nil {
 		return nil, err
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/ellcrys/docker/api/types/strslice"
 	"github.com/ellcrys/docker/builder/dockerfile/command"
 	"github.com/ellcrys/docker/builder/dockerfile/parser"
 	"github.com/gotestyourself/gotestyourself/assert"
@@ -196,3 +197,101 @@ func TestErrorCases(t *testing.T) {
 		assert.Check(t, is.ErrorContains(err, c.expectedError))
 	}
 }
+
+func TestParseHeredocs(t *testing.T) {
+	testCases := []struct {
+		dockerfile string
+		expected   interface{}
+	}{
+		{
+			dockerfile: "RUN <<EOF\necho $HOME\nEOF",
+			expected: ShellDependantCmdLine{
+				CmdLine:      []string{"echo $HOME\n"},
+				PrependShell: true,
+			},
+		},
+		{
+			dockerfile: "RUN python3 <<-'EOF'\n\tprint('hello')\n\tEOF",
+			expected: ShellDependantCmdLine{
+				CmdLine:      []string{"python3 <<-'EOF'\nprint('hello')\nEOF"},
+				PrependShell: true,
+			},
+		},
+		{
+			dockerfile: "COPY --chown=1000 <<a.txt file.txt <<b.txt /dest/\nA\na.txt\nB\nb.txt",
+			expected:   SourcesAndDest{"<<a.txt", "file.txt", "<<b.txt", "/dest/"},
+		},
+	}
+
+	for _, tc := range testCases {
+		ast, err := parser.Parse(strings.NewReader(tc.dockerfile))
+		assert.NilError(t, err)
+		cmd, err := ParseInstruction(ast.AST.Children[0])
+		assert.NilError(t, err)
+		switch c := cmd.(type) {
+		case *RunCommand:
+			assert.Check(t, is.DeepEqual(tc.expected, c.ShellDependantCmdLine))
+		case *CopyCommand:
+			assert.Check(t, is.DeepEqual(tc.expected, c.SourcesAndDest))
+			h, ok := c.Heredoc("<<b.txt")
+			assert.Check(t, ok)
+			assert.Check(t, is.Equal("B\n", h.Content))
+		}
+	}
+}
+
+func TestParseHeredocErrors(t *testing.T) {
+	testCases := []struct {
+		dockerfile  string
+		expectedErr string
+	}{
+		{
+			dockerfile:  "FROM busybox\nRUN <<EOF\necho hello",
+			expectedErr: "Dockerfile parse error line 2: unterminated heredoc <<EOF",
+		},
+		{
+			dockerfile:  "FROM busybox\nCOPY foo<<EOF /dest\nhello\nEOF",
+			expectedErr: "heredoc <<EOF must be a COPY source",
+		},
+	}
+
+	for _, tc := range testCases {
+		ast, err := parser.Parse(strings.NewReader(tc.dockerfile))
+		assert.NilError(t, err)
+		_, _, err = Parse(ast.AST)
+		assert.Check(t, is.ErrorContains(err, tc.expectedErr), tc.dockerfile)
+	}
+}
+
+func TestParseRunMounts(t *testing.T) {
+	ast, err := parser.Parse(strings.NewReader("RUN --mount=type=cache,target=/root/.cache --mount=type=secret,id=aws,required cat /run/secrets/aws"))
+	assert.NilError(t, err)
+	cmd, err := ParseInstruction(ast.AST.Children[0])
+	assert.NilError(t, err)
+	run, ok := cmd.(*RunCommand)
+	assert.Assert(t, ok)
+	assert.Check(t, is.DeepEqual(run.CmdLine, strslice.StrSlice{"cat /run/secrets/aws"}))
+	assert.Check(t, is.DeepEqual(run.Mounts, []*RunMount{
+		{Type: MountTypeCache, ID: "/root/.cache", Target: "/root/.cache"},
+		{Type: MountTypeSecret, ID: "aws", Target: "/run/secrets/aws", ReadOnly: true, Required: true},
+	}))
+
+	testCases := []struct {
+		mount       string
+		expectedErr string
+	}{
+		{mount: "target=/cache", expectedErr: "has no type"},
+		{mount: "type=bind,target=/src", expectedErr: "unsupported mount type"},
+		{mount: "type=cache", expectedErr: "has no target"},
+		{mount: "type=cache,target=cache", expectedErr: "must be an absolute path"},
+		{mount: "type=cache,target=/cache,size=1G", expectedErr: "unknown field"},
+		{mount: "type=secret", expectedErr: "has no id or target"},
+		{mount: "type=secret,id=../aws", expectedErr: "invalid secret id"},
+	}
+	for _, tc := range testCases {
+		ast, err := parser.Parse(strings.NewReader("RUN --mount=" + tc.mount + " true"))
+		assert.NilError(t, err)
+		_, err = ParseInstruction(ast.AST.Children[0])
+		assert.Check(t, is.ErrorContains(err, tc.expectedErr), tc.mount)
+	}
+}
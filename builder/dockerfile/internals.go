@@ -17,6 +17,7 @@ import (
 	"github.com/ellcrys/docker/api/types"
 	"github.com/ellcrys/docker/api/types/backend"
 	"github.com/ellcrys/docker/api/types/container"
+	"github.com/ellcrys/docker/api/types/mount"
 	"github.com/ellcrys/docker/builder"
 	"github.com/ellcrys/docker/image"
 	"github.com/ellcrys/docker/pkg/archive"
@@ -419,8 +420,9 @@ func (b *Builder) probeAndCreate(dispatchState *dispatchState, runConfig *contai
 	return container.ID, err
 }
 
-func (b *Builder) create(runConfig *container.Config) (string, error) {
+func (b *Builder) create(runConfig *container.Config, mounts ...mount.Mount) (string, error) {
 	hostConfig := hostConfigFromOptions(b.options)
+	hostConfig.Mounts = mounts
 	container, err := b.containerManager.Create(runConfig, hostConfig)
 	if err != nil {
 		return "", err
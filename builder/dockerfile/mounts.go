@@ -0,0 +1,168 @@
+package dockerfile // import "github.com/ellcrys/docker/builder/dockerfile"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ellcrys/docker/api/types/mount"
+	"github.com/ellcrys/docker/api/types/strslice"
+	"github.com/ellcrys/docker/builder/dockerfile/instructions"
+	"github.com/ellcrys/docker/pkg/idtools"
+	"github.com/moby/buildkit/session/filesync"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// secretsDirName is the name of the directory synced by the client session
+// the secrets of RUN --mount=type=secret are read from.
+const secretsDirName = "secrets"
+
+// runMounts returns the mounts of the container of a RUN instruction, and a
+// function releasing them once the container has run.
+func (b *Builder) runMounts(runMounts []*instructions.RunMount) ([]mount.Mount, func(), error) {
+	var (
+		mounts   []mount.Mount
+		releases []func()
+		secrets  []*instructions.RunMount
+	)
+	release := func() {
+		for _, r := range releases {
+			r()
+		}
+	}
+
+	for _, m := range runMounts {
+		switch m.Type {
+		case instructions.MountTypeCache:
+			if b.cacheMounts == nil {
+				release()
+				return nil, nil, errors.New("cache mounts are not supported by this builder")
+			}
+			dir, r, err := b.cacheMounts.Get(m.ID)
+			if err != nil {
+				release()
+				return nil, nil, err
+			}
+			releases = append(releases, r)
+			mounts = append(mounts, mount.Mount{
+				Type:     mount.TypeBind,
+				Source:   dir,
+				Target:   m.Target,
+				ReadOnly: m.ReadOnly,
+			})
+		case instructions.MountTypeSecret:
+			secrets = append(secrets, m)
+		}
+	}
+
+	if len(secrets) > 0 {
+		dir, err := b.syncSecrets(secrets)
+		if dir != "" {
+			releases = append(releases, func() {
+				if err := os.RemoveAll(dir); err != nil {
+					logrus.WithError(err).Warnf("failed to remove build secrets %s", dir)
+				}
+			})
+		}
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+		for _, m := range secrets {
+			p := filepath.Join(dir, m.ID)
+			if dir == "" || !fileExists(p) {
+				if m.Required {
+					release()
+					return nil, nil, errors.Errorf("secret %s is required but was not supplied", m.ID)
+				}
+				continue
+			}
+			mounts = append(mounts, mount.Mount{
+				Type:     mount.TypeBind,
+				Source:   p,
+				Target:   m.Target,
+				ReadOnly: true,
+			})
+		}
+	}
+	return mounts, release, nil
+}
+
+// syncSecrets copies the secrets supplied by the client session to a
+// temporary directory, readable only by the root user of the container.
+func (b *Builder) syncSecrets(secrets []*instructions.RunMount) (string, error) {
+	var required bool
+	for _, m := range secrets {
+		required = required || m.Required
+	}
+	if b.options.SessionID == "" || b.sessionGetter == nil {
+		if required {
+			return "", errors.New("secret mounts require a build session")
+		}
+		return "", nil
+	}
+
+	caller, err := b.sessionGetter.Get(b.clientCtx, b.options.SessionID)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get session for %s", b.options.SessionID)
+	}
+	dir, err := ioutil.TempDir("", "docker-build-secrets")
+	if err != nil {
+		return "", err
+	}
+	ids := make([]string, 0, len(secrets))
+	for _, m := range secrets {
+		ids = append(ids, m.ID)
+	}
+	if err := filesync.FSSync(b.clientCtx, caller, filesync.FSSendRequestOpt{
+		Name:             secretsDirName,
+		IncludePatterns:  ids,
+		OverrideExcludes: true,
+		DestDir:          dir,
+	}); err != nil {
+		if required {
+			return dir, errors.Wrap(err, "failed to get secrets from the client")
+		}
+		logrus.WithError(err).Debug("no secrets supplied by the client")
+		return dir, nil
+	}
+
+	var rootIDs idtools.IDPair
+	if b.idMappings != nil {
+		rootIDs = b.idMappings.RootPair()
+	}
+	for _, id := range ids {
+		p := filepath.Join(dir, id)
+		fi, err := os.Lstat(p)
+		if err != nil {
+			continue
+		}
+		if !fi.Mode().IsRegular() {
+			return dir, errors.Errorf("secret %s is not a regular file", id)
+		}
+		if err := os.Chmod(p, 0400); err != nil {
+			return dir, err
+		}
+		if err := os.Chown(p, rootIDs.UID, rootIDs.GID); err != nil {
+			return dir, err
+		}
+	}
+	return dir, nil
+}
+
+func fileExists(p string) bool {
+	_, err := os.Stat(p)
+	return err == nil
+}
+
+// prependMountsOnCmd adds the mounts of a RUN instruction to the command
+// used for probeCache() and committed, so that the cache is not used when
+// the mounts change. The content of the mounts is not part of the cache key.
+func prependMountsOnCmd(mounts []*instructions.RunMount, cmd strslice.StrSlice) strslice.StrSlice {
+	var flags []string
+	for _, m := range mounts {
+		flags = append(flags, "--mount="+m.String())
+	}
+	return strslice.StrSlice(append(flags, cmd...))
+}
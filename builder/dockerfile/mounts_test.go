@@ -0,0 +1,54 @@
+package dockerfile // import "github.com/ellcrys/docker/builder/dockerfile"
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ellcrys/docker/api/types/mount"
+	"github.com/ellcrys/docker/api/types/strslice"
+	"github.com/ellcrys/docker/builder/cachemounts"
+	"github.com/ellcrys/docker/builder/dockerfile/instructions"
+	"github.com/ellcrys/docker/pkg/idtools"
+	"github.com/gotestyourself/gotestyourself/assert"
+	is "github.com/gotestyourself/gotestyourself/assert/cmp"
+)
+
+func TestRunMounts(t *testing.T) {
+	root, err := ioutil.TempDir("", "cache-mounts")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+
+	b := newBuilderWithMockBackend()
+	b.cacheMounts, err = cachemounts.NewStore(root, idtools.IDPair{UID: os.Getuid(), GID: os.Getgid()})
+	assert.NilError(t, err)
+
+	mounts, release, err := b.runMounts([]*instructions.RunMount{
+		{Type: instructions.MountTypeCache, ID: "go-build", Target: "/root/.cache/go-build"},
+		{Type: instructions.MountTypeSecret, ID: "aws", Target: "/run/secrets/aws", ReadOnly: true},
+	})
+	assert.NilError(t, err)
+	defer release()
+	assert.Assert(t, is.Len(mounts, 1), "a secret which is not required is not mounted without a session")
+	assert.Check(t, is.Equal(mounts[0].Type, mount.TypeBind))
+	assert.Check(t, is.Equal(mounts[0].Target, "/root/.cache/go-build"))
+	_, err = os.Stat(mounts[0].Source)
+	assert.Check(t, err)
+
+	_, _, err = b.runMounts([]*instructions.RunMount{
+		{Type: instructions.MountTypeSecret, ID: "aws", Target: "/run/secrets/aws", ReadOnly: true, Required: true},
+	})
+	assert.Check(t, is.ErrorContains(err, "secret mounts require a build session"))
+}
+
+func TestPrependMountsOnCmd(t *testing.T) {
+	cmd := prependMountsOnCmd([]*instructions.RunMount{
+		{Type: instructions.MountTypeCache, ID: "/cache", Target: "/cache", ReadOnly: true},
+		{Type: instructions.MountTypeSecret, ID: "aws", Target: "/run/secrets/aws", ReadOnly: true},
+	}, strslice.StrSlice{"/bin/sh", "-c", "true"})
+	assert.Check(t, is.DeepEqual(cmd, strslice.StrSlice{
+		"--mount=type=cache,id=/cache,target=/cache,readonly",
+		"--mount=type=secret,id=aws,target=/run/secrets/aws",
+		"/bin/sh", "-c", "true",
+	}))
+}
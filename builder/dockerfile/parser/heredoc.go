@@ -0,0 +1,132 @@
+package parser // import "github.com/ellcrys/docker/builder/dockerfile/parser"
+
+import (
+	"bufio"
+	"strings"
+
+	"github.com/ellcrys/docker/builder/dockerfile/command"
+)
+
+// Heredoc is a here-document following an instruction, as in:
+//
+//   RUN <<EOF
+//   apt-get update
+//   apt-get install -y curl
+//   EOF
+//
+type Heredoc struct {
+	Name       string // the delimiter word, without quotes
+	Content    string // the lines up to the delimiter, each ending with a newline
+	Expand     bool   // whether variables are expanded, i.e. the delimiter is not quoted
+	Chomp      bool   // whether leading tabs are removed from the lines ("<<-")
+	Terminated bool   // whether the delimiter line was found before the end of the Dockerfile
+}
+
+// heredocCommands are the instructions which can be followed by heredocs.
+var heredocCommands = map[string]bool{
+	command.Copy: true,
+	command.Run:  true,
+}
+
+// parseHeredocMarkers returns the heredocs started by "<<NAME", "<<-NAME",
+// "<<'NAME'" or "<<\"NAME\"" markers in args, in the order their content
+// follows the instruction. Markers in quotes, or escaped, are ignored.
+func parseHeredocMarkers(args string, escapeToken rune) []Heredoc {
+	var heredocs []Heredoc
+	var quote byte
+	for i := 0; i < len(args); i++ {
+		c := args[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if quote == '"' && rune(c) == escapeToken {
+				i++
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case rune(c) == escapeToken:
+			i++
+		case strings.HasPrefix(args[i:], "<<<"):
+			// a here-string
+			for i+1 < len(args) && args[i+1] == '<' {
+				i++
+			}
+		case strings.HasPrefix(args[i:], "<<"):
+			h, n := parseHeredocMarker(args[i+2:])
+			if h.Name == "" {
+				// a "<<" not followed by a delimiter
+				i++
+				continue
+			}
+			heredocs = append(heredocs, h)
+			i += 1 + n
+		}
+	}
+	return heredocs
+}
+
+// parseHeredocMarker parses the part of a heredoc marker after "<<", and
+// returns the number of bytes it is made of.
+func parseHeredocMarker(s string) (Heredoc, int) {
+	h := Heredoc{Expand: true}
+	n := 0
+	if strings.HasPrefix(s, "-") {
+		h.Chomp = true
+		n++
+	}
+	if n < len(s) && (s[n] == '\'' || s[n] == '"') {
+		q := s[n]
+		end := strings.IndexByte(s[n+1:], q)
+		if end < 0 || !isHeredocName(s[n+1:n+1+end]) {
+			return Heredoc{}, 0
+		}
+		h.Name = s[n+1 : n+1+end]
+		h.Expand = false
+		return h, n + end + 2
+	}
+	end := n
+	for end < len(s) && isHeredocNameChar(s[end]) {
+		end++
+	}
+	h.Name = s[n:end]
+	return h, end
+}
+
+func isHeredocName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isHeredocNameChar(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isHeredocNameChar(c byte) bool {
+	return c == '_' || c == '-' || c == '.' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// readHeredoc reads the content of the heredoc from the lines following the
+// instruction, up to its delimiter line. It returns the number of lines read.
+func readHeredoc(scanner *bufio.Scanner, h Heredoc) (Heredoc, int) {
+	var content strings.Builder
+	lines := 0
+	for scanner.Scan() {
+		lines++
+		line := scanner.Text()
+		if h.Chomp {
+			line = strings.TrimLeft(line, "\t")
+		}
+		if line == h.Name {
+			h.Terminated = true
+			break
+		}
+		content.WriteString(line)
+		content.WriteString("\n")
+	}
+	h.Content = content.String()
+	return h, lines
+}
@@ -34,6 +34,7 @@ type Node struct {
 	Attributes map[string]bool // special attributes for this node
 	Original   string          // original line used before parsing
 	Flags      []string        // only top Node should have this set
+	Heredocs   []Heredoc       // only top Node should have this set
 	StartLine  int             // the line in the original dockerfile where the node begins
 	endLine    int             // the line in the original dockerfile where the node ends
 }
@@ -269,6 +270,17 @@ func Parse(rwc io.Reader) (*Result, error) {
 		if err != nil {
 			return nil, err
 		}
+		if heredocCommands[child.Value] && !child.Attributes["json"] {
+			_, _, args, err := splitCommand(line)
+			if err != nil {
+				return nil, err
+			}
+			for _, h := range parseHeredocMarkers(args, d.escapeToken) {
+				h, n := readHeredoc(scanner, h)
+				currentLine += n
+				child.Heredocs = append(child.Heredocs, h)
+			}
+		}
 		root.AddChild(child, startLine, currentLine)
 	}
 
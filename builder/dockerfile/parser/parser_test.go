@@ -172,3 +172,50 @@ func TestParseReturnsScannerErrors(t *testing.T) {
 	_, err := Parse(dockerfile)
 	assert.Check(t, is.Error(err, "dockerfile line greater than max allowed size of 65535"))
 }
+
+func TestParseHeredocs(t *testing.T) {
+	dockerfile := "FROM busybox\n" +
+		"RUN <<EOF\n" +
+		"echo hello\n" +
+		"  echo $HOME\n" +
+		"EOF\n" +
+		"COPY <<-\"one\" <<two /dest/\n" +
+		"\tfirst $file\n" +
+		"\tone\n" +
+		"second\n" +
+		"two\n" +
+		"RUN echo \"<<NOT\" '<<NOT' \\<<NOT <<<NOT\n" +
+		"RUN [\"cat\", \"<<NOT\"]\n" +
+		"CMD cat <<NOT\n"
+
+	result, err := Parse(strings.NewReader(dockerfile))
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(result.AST.Children, 6))
+
+	run := result.AST.Children[1]
+	assert.Check(t, is.DeepEqual([]Heredoc{
+		{Name: "EOF", Content: "echo hello\n  echo $HOME\n", Expand: true, Terminated: true},
+	}, run.Heredocs))
+	assert.Check(t, is.Equal(2, run.StartLine))
+
+	copyNode := result.AST.Children[2]
+	assert.Check(t, is.DeepEqual([]Heredoc{
+		{Name: "one", Content: "first $file\n", Chomp: true, Terminated: true},
+		{Name: "two", Content: "second\n", Expand: true, Terminated: true},
+	}, copyNode.Heredocs))
+	assert.Check(t, is.Equal(6, copyNode.StartLine))
+
+	for _, node := range result.AST.Children[3:] {
+		assert.Check(t, is.Len(node.Heredocs, 0), node.Original)
+	}
+	assert.Check(t, is.Equal(11, result.AST.Children[3].StartLine))
+}
+
+func TestParseUnterminatedHeredoc(t *testing.T) {
+	result, err := Parse(strings.NewReader("FROM busybox\nRUN <<EOF\necho hello\n EOF\n"))
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(result.AST.Children, 2))
+	assert.Check(t, is.DeepEqual([]Heredoc{
+		{Name: "EOF", Content: "echo hello\n EOF\n", Expand: true},
+	}, result.AST.Children[1].Heredocs))
+}
@@ -44,6 +44,40 @@ func (s *Lex) ProcessWords(word string, env []string) ([]string, error) {
 	return words, err
 }
 
+// ProcessHeredoc will use the 'env' list of environment variables, and
+// replace any env var references in the content of a heredoc. Unlike in a
+// word, quotes are kept as-is, and the escape token only escapes '$' and
+// itself, as in the content of a shell here-document.
+func (s *Lex) ProcessHeredoc(content string, env []string) (string, error) {
+	sw := &shellWord{
+		envs:        env,
+		escapeToken: s.escapeToken,
+	}
+	sw.scanner.Init(strings.NewReader(content))
+
+	var result bytes.Buffer
+	for sw.scanner.Peek() != scanner.EOF {
+		ch := sw.scanner.Peek()
+		switch ch {
+		case '$':
+			value, err := sw.processDollar()
+			if err != nil {
+				return "", errors.Wrap(err, "failed to process heredoc")
+			}
+			result.WriteString(value)
+		case sw.escapeToken:
+			sw.scanner.Next()
+			if next := sw.scanner.Peek(); next == '$' || next == sw.escapeToken {
+				ch = sw.scanner.Next()
+			}
+			result.WriteRune(ch)
+		default:
+			result.WriteRune(sw.scanner.Next())
+		}
+	}
+	return result.String(), nil
+}
+
 func (s *Lex) process(word string, env []string) (string, []string, error) {
 	sw := &shellWord{
 		envs:        env,
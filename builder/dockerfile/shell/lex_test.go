@@ -150,3 +150,25 @@ func TestGetEnv(t *testing.T) {
 		t.Fatal("8 - 'car' should map to 'hat'")
 	}
 }
+
+func TestProcessHeredoc(t *testing.T) {
+	envs := []string{"PWD=/home", "SHELL=bash", "EMPTY="}
+	shlex := NewLex('\\')
+	for _, tc := range []struct {
+		content  string
+		expected string
+	}{
+		{content: "cd $PWD\n", expected: "cd /home\n"},
+		{content: "echo \"${SHELL}\" '$PWD'\n", expected: "echo \"bash\" '/home'\n"},
+		{content: "echo \\$PWD \\\\ \\n\n", expected: "echo $PWD \\ \\n\n"},
+		{content: "${EMPTY:-default} ${SHELL:+set}\n", expected: "default set\n"},
+	} {
+		result, err := shlex.ProcessHeredoc(tc.content, envs)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", tc.content, err)
+		}
+		if result != tc.expected {
+			t.Fatalf("%q was supposed to result in %q, but got %q instead", tc.content, tc.expected, result)
+		}
+	}
+}
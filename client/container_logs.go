@@ -72,6 +72,13 @@ func (cli *Client) ContainerLogs(ctx context.Context, container string, options
 	}
 	query.Set("tail", options.Tail)
 
+	if options.Pattern != "" {
+		query.Set("pattern", options.Pattern)
+		if options.Regex {
+			query.Set("regex", "1")
+		}
+	}
+
 	resp, err := cli.get(ctx, "/containers/"+container+"/logs", query, nil)
 	if err != nil {
 		return nil, wrapResponseError(err, resp, "container", container)
@@ -171,10 +171,13 @@ type SystemAPIClient interface {
 // VolumeAPIClient defines API client methods for the volumes
 type VolumeAPIClient interface {
 	VolumeCreate(ctx context.Context, options volumetypes.VolumeCreateBody) (types.Volume, error)
+	VolumeExport(ctx context.Context, volumeID string, options types.VolumeExportOptions) (io.ReadCloser, error)
+	VolumeImport(ctx context.Context, source io.Reader, options types.VolumeImportOptions) (types.Volume, error)
 	VolumeInspect(ctx context.Context, volumeID string) (types.Volume, error)
 	VolumeInspectWithRaw(ctx context.Context, volumeID string) (types.Volume, []byte, error)
 	VolumeList(ctx context.Context, filter filters.Args) (volumetypes.VolumeListOKBody, error)
 	VolumeRemove(ctx context.Context, volumeID string, force bool) error
+	VolumeSnapshot(ctx context.Context, volumeID string, options volumetypes.VolumeSnapshotBody) (types.Volume, error)
 	VolumesPrune(ctx context.Context, pruneFilter filters.Args) (types.VolumesPruneReport, error)
 }
 
@@ -11,6 +11,11 @@ import (
 // VolumeCreate creates a volume in the docker host.
 func (cli *Client) VolumeCreate(ctx context.Context, options volumetypes.VolumeCreateBody) (types.Volume, error) {
 	var volume types.Volume
+	if options.From != "" {
+		if err := cli.NewVersionError("1.38", "volume create from another volume"); err != nil {
+			return volume, err
+		}
+	}
 	resp, err := cli.post(ctx, "/volumes/create", nil, options, nil)
 	if err != nil {
 		return volume, err
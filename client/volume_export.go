@@ -0,0 +1,27 @@
+package client // import "github.com/ellcrys/docker/client"
+
+import (
+	"context"
+	"io"
+	"net/url"
+
+	"github.com/ellcrys/docker/api/types"
+)
+
+// VolumeExport retrieves a tar archive of the content of the volume. It's up
+// to the caller to close the stream.
+func (cli *Client) VolumeExport(ctx context.Context, volumeID string, options types.VolumeExportOptions) (io.ReadCloser, error) {
+	if err := cli.NewVersionError("1.38", "volume export"); err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	if options.Compression != "" {
+		query.Set("compression", options.Compression)
+	}
+
+	resp, err := cli.get(ctx, "/volumes/"+volumeID+"/export", query, nil)
+	if err != nil {
+		return nil, wrapResponseError(err, resp, "volume", volumeID)
+	}
+	return resp.body, nil
+}
@@ -0,0 +1,43 @@
+package client // import "github.com/ellcrys/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/ellcrys/docker/api/types"
+)
+
+// VolumeImport extracts the tar archive read from source into a volume,
+// created if it does not exist. The archive may be compressed.
+func (cli *Client) VolumeImport(ctx context.Context, source io.Reader, options types.VolumeImportOptions) (types.Volume, error) {
+	var volume types.Volume
+	if err := cli.NewVersionError("1.38", "volume import"); err != nil {
+		return volume, err
+	}
+	query := url.Values{}
+	if options.Name != "" {
+		query.Set("name", options.Name)
+	}
+	if options.Driver != "" {
+		query.Set("driver", options.Driver)
+	}
+	if len(options.Labels) > 0 {
+		labels, err := json.Marshal(options.Labels)
+		if err != nil {
+			return volume, err
+		}
+		query.Set("labels", string(labels))
+	}
+
+	headers := http.Header{"Content-Type": {"application/x-tar"}}
+	resp, err := cli.postRaw(ctx, "/volumes/import", query, source, headers)
+	if err != nil {
+		return volume, err
+	}
+	err = json.NewDecoder(resp.body).Decode(&volume)
+	ensureReaderClosed(resp)
+	return volume, err
+}
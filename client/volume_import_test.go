@@ -0,0 +1,77 @@
+package client // import "github.com/ellcrys/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ellcrys/docker/api/types"
+)
+
+func TestVolumeImportError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+
+	_, err := client.VolumeImport(context.Background(), strings.NewReader("archive"), types.VolumeImportOptions{})
+	if err == nil || err.Error() != "Error response from daemon: Server error" {
+		t.Fatalf("expected a Server Error, got %v", err)
+	}
+}
+
+func TestVolumeImport(t *testing.T) {
+	expectedURL := "/volumes/import"
+
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != expectedURL {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != "POST" {
+				return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+			}
+			query := req.URL.Query()
+			if name := query.Get("name"); name != "myvolume" {
+				return nil, fmt.Errorf("expected name 'myvolume', got %s", name)
+			}
+			if labels := query.Get("labels"); labels != `{"key":"value"}` {
+				return nil, fmt.Errorf("expected labels '{\"key\":\"value\"}', got %s", labels)
+			}
+			archive, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			if string(archive) != "archive" {
+				return nil, fmt.Errorf("expected body 'archive', got %s", archive)
+			}
+
+			content, err := json.Marshal(types.Volume{
+				Name:   "myvolume",
+				Driver: "local",
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       ioutil.NopCloser(bytes.NewReader(content)),
+			}, nil
+		}),
+	}
+
+	volume, err := client.VolumeImport(context.Background(), strings.NewReader("archive"), types.VolumeImportOptions{
+		Name:   "myvolume",
+		Labels: map[string]string{"key": "value"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if volume.Name != "myvolume" {
+		t.Fatalf("expected volume.Name to be 'myvolume', got %s", volume.Name)
+	}
+}
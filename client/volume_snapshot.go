@@ -0,0 +1,25 @@
+package client // import "github.com/ellcrys/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ellcrys/docker/api/types"
+	volumetypes "github.com/ellcrys/docker/api/types/volume"
+)
+
+// VolumeSnapshot creates a volume from a snapshot of the volume volumeID in
+// the docker host.
+func (cli *Client) VolumeSnapshot(ctx context.Context, volumeID string, options volumetypes.VolumeSnapshotBody) (types.Volume, error) {
+	var volume types.Volume
+	if err := cli.NewVersionError("1.38", "volume snapshot"); err != nil {
+		return volume, err
+	}
+	resp, err := cli.post(ctx, "/volumes/"+volumeID+"/snapshot", nil, options, nil)
+	if err != nil {
+		return volume, wrapResponseError(err, resp, "volume", volumeID)
+	}
+	err = json.NewDecoder(resp.body).Decode(&volume)
+	ensureReaderClosed(resp)
+	return volume, err
+}
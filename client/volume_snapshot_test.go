@@ -0,0 +1,82 @@
+package client // import "github.com/ellcrys/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/ellcrys/docker/api/types"
+	volumetypes "github.com/ellcrys/docker/api/types/volume"
+)
+
+func TestVolumeSnapshotError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+
+	_, err := client.VolumeSnapshot(context.Background(), "volume", volumetypes.VolumeSnapshotBody{})
+	if err == nil || err.Error() != "Error response from daemon: Server error" {
+		t.Fatalf("expected a Server Error, got %v", err)
+	}
+}
+
+func TestVolumeSnapshotVersion(t *testing.T) {
+	client := &Client{
+		client:  newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+		version: "1.37",
+	}
+
+	_, err := client.VolumeSnapshot(context.Background(), "volume", volumetypes.VolumeSnapshotBody{})
+	if err == nil || err.Error() != `"volume snapshot" requires API version 1.38, but the Docker daemon API version is 1.37` {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+}
+
+func TestVolumeSnapshot(t *testing.T) {
+	expectedURL := "/volumes/volume/snapshot"
+
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != expectedURL {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+
+			if req.Method != "POST" {
+				return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+			}
+
+			var body volumetypes.VolumeSnapshotBody
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+			if body.Name != "snapshot" {
+				return nil, fmt.Errorf("expected snapshot name 'snapshot', got %s", body.Name)
+			}
+
+			content, err := json.Marshal(types.Volume{
+				Name:       "snapshot",
+				Driver:     "local",
+				Mountpoint: "mountpoint",
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       ioutil.NopCloser(bytes.NewReader(content)),
+			}, nil
+		}),
+	}
+
+	volume, err := client.VolumeSnapshot(context.Background(), "volume", volumetypes.VolumeSnapshotBody{Name: "snapshot"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if volume.Name != "snapshot" {
+		t.Fatalf("expected volume.Name to be 'snapshot', got %s", volume.Name)
+	}
+}
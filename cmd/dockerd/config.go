@@ -58,7 +58,18 @@ func installCommonConfigFlags(conf *config.Config, flags *pflag.FlagSet) {
 	flags.StringVar(&conf.CorsHeaders, "api-cors-header", "", "Set CORS headers in the Engine API")
 	flags.IntVar(&maxConcurrentDownloads, "max-concurrent-downloads", config.DefaultMaxConcurrentDownloads, "Set the max concurrent downloads for each pull")
 	flags.IntVar(&maxConcurrentUploads, "max-concurrent-uploads", config.DefaultMaxConcurrentUploads, "Set the max concurrent uploads for each push")
+	flags.IntVar(&conf.MaxDownloadChunks, "max-download-chunks", 1, "Set the max parallel ranged requests to download a single large layer with")
+	flags.StringVar(&conf.LayerCompression, "layer-compression", "gzip", "Set the compression of layers uploaded on push (gzip, zstd)")
+	flags.StringVar(&conf.SignaturePolicy, "signature-policy", "", "Path to the signature policy file images are verified against on pull and run")
 	flags.IntVar(&conf.ShutdownTimeout, "shutdown-timeout", defaultShutdownTimeout, "Set the default shutdown timeout")
+	flags.BoolVar(&conf.EventsJournal, "events-journal", false, "Persist events to disk to allow querying past events")
+	conf.EventsJournalMaxSize = opts.MemBytes(config.DefaultEventsJournalMaxSize)
+	flags.Var(&conf.EventsJournalMaxSize, "events-journal-max-size", "Set the maximum size of the events journal")
+	flags.StringVar(&conf.EventsJournalMaxAge, "events-journal-max-age", config.DefaultEventsJournalMaxAge, "Set the age after which events are removed from the events journal")
+	flags.IntVar(&conf.ImageGCHighWatermark, "image-gc-high-watermark", 0, "Remove unused images when the disk usage of the storage driver goes over this percentage (0 to disable)")
+	flags.IntVar(&conf.ImageGCLowWatermark, "image-gc-low-watermark", 0, "Stop removing unused images when the disk usage goes under this percentage (default 10 under the high watermark)")
+	flags.StringVar(&conf.ImageGCInterval, "image-gc-interval", config.DefaultImageGCInterval, "Set the time between two checks of the disk usage by the image garbage collector")
+	flags.Var(opts.NewNamedListOptsRef("image-gc-protect", &conf.ImageGCProtect, nil), "image-gc-protect", "Protect images from the image garbage collector (label=<key>[=<value>], reference=<pattern>)")
 	flags.IntVar(&conf.NetworkDiagnosticPort, "network-diagnostic-port", 0, "TCP port number of the network diagnostic server")
 	flags.MarkHidden("network-diagnostic-port")
 
@@ -85,10 +96,14 @@ func installCommonConfigFlags(conf *config.Config, flags *pflag.FlagSet) {
 func installRegistryServiceFlags(options *registry.ServiceOptions, flags *pflag.FlagSet) {
 	ana := opts.NewNamedListOptsRef("allow-nondistributable-artifacts", &options.AllowNondistributableArtifacts, registry.ValidateIndexName)
 	mirrors := opts.NewNamedListOptsRef("registry-mirrors", &options.Mirrors, registry.ValidateMirror)
+	hostMirrors := opts.NewNamedListOptsRef("registry-host-mirrors", &options.HostMirrors, registry.ValidateHostMirror)
+	rewrites := opts.NewNamedListOptsRef("registry-rewrites", &options.Rewrites, registry.ValidateRewrite)
 	insecureRegistries := opts.NewNamedListOptsRef("insecure-registries", &options.InsecureRegistries, registry.ValidateIndexName)
 
 	flags.Var(ana, "allow-nondistributable-artifacts", "Allow push of nondistributable artifacts to registry")
 	flags.Var(mirrors, "registry-mirror", "Preferred Docker registry mirror")
+	flags.Var(hostMirrors, "registry-host-mirror", "Preferred mirror of a registry (registry=mirror)")
+	flags.Var(rewrites, "registry-rewrite", "Rewrite the references of the images pulled (source=target, e.g. docker.io/library/*=registry.corp/hub/*)")
 	flags.Var(insecureRegistries, "insecure-registry", "Enable insecure registry communication")
 
 	if runtime.GOOS != "windows" {
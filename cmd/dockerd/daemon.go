@@ -27,6 +27,7 @@ import (
 	swarmrouter "github.com/ellcrys/docker/api/server/router/swarm"
 	systemrouter "github.com/ellcrys/docker/api/server/router/system"
 	"github.com/ellcrys/docker/api/server/router/volume"
+	"github.com/ellcrys/docker/builder/cachemounts"
 	"github.com/ellcrys/docker/builder/dockerfile"
 	"github.com/ellcrys/docker/builder/fscache"
 	"github.com/ellcrys/docker/cli/debug"
@@ -265,12 +266,17 @@ func newRouterOptions(config *config.Config, daemon *daemon.Daemon) (routerOptio
 		return opts, errors.Wrap(err, "failed to create fscache")
 	}
 
-	manager, err := dockerfile.NewBuildManager(daemon.BuilderBackend(), sm, buildCache, daemon.IDMappings())
+	cacheMounts, err := cachemounts.NewStore(filepath.Join(builderStateDir, "cache-mounts"), daemon.IDMappings().RootPair())
 	if err != nil {
 		return opts, err
 	}
 
-	bb, err := buildbackend.NewBackend(daemon.ImageService(), manager, buildCache)
+	manager, err := dockerfile.NewBuildManager(daemon.BuilderBackend(), sm, buildCache, cacheMounts, daemon.IDMappings())
+	if err != nil {
+		return opts, err
+	}
+
+	bb, err := buildbackend.NewBackend(daemon.ImageService(), manager, buildCache, cacheMounts)
 	if err != nil {
 		return opts, errors.Wrap(err, "failed to create buildmanager")
 	}
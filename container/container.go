@@ -22,6 +22,7 @@ import (
 	"github.com/ellcrys/docker/daemon/exec"
 	"github.com/ellcrys/docker/daemon/logger"
 	"github.com/ellcrys/docker/daemon/logger/jsonfilelog"
+	"github.com/ellcrys/docker/daemon/logger/tee"
 	"github.com/ellcrys/docker/daemon/network"
 	"github.com/ellcrys/docker/image"
 	"github.com/ellcrys/docker/layer"
@@ -374,8 +375,9 @@ func (container *Container) StartLogger() (logger.Logger, error) {
 		DaemonName:          "docker",
 	}
 
-	// Set logging file for "json-logger"
-	if cfg.Type == jsonfilelog.Name {
+	// Set logging file for "json-logger", also when it is one of the drivers
+	// of the "tee" logger
+	if cfg.Type == jsonfilelog.Name || (cfg.Type == tee.Name && tee.HasDriver(cfg.Config, jsonfilelog.Name)) {
 		info.LogPath, err = container.GetRootResourcePath(fmt.Sprintf("%s-json.log", container.ID))
 		if err != nil {
 			return nil, err
@@ -600,7 +602,11 @@ func (container *Container) startLogging() error {
 		return fmt.Errorf("failed to initialize logging driver: %v", err)
 	}
 
-	copier := logger.NewCopier(map[string]io.Reader{"stdout": container.StdoutPipe(), "stderr": container.StderrPipe()}, l)
+	copier, err := logger.NewCopierWithLogOpts(map[string]io.Reader{"stdout": container.StdoutPipe(), "stderr": container.StderrPipe()}, l, container.HostConfig.LogConfig.Config)
+	if err != nil {
+		l.Close()
+		return fmt.Errorf("failed to initialize logging driver: %v", err)
+	}
 	container.LogCopier = copier
 	copier.Run()
 	container.LogDriver = l
@@ -0,0 +1,104 @@
+// Command secret-file-driver is a secret provider plugin serving the secrets
+// stored in a local encrypted file. It is meant for testing secrets created
+// with a driver without an external secret store.
+//
+// To store a secret, pipe its value to `secret-file-driver set NAME`, then
+// start the plugin and create the swarm secret with
+// `docker secret create --driver secret-file NAME`.
+package main
+
+import (
+	"crypto/rand"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/ellcrys/docker/pkg/secretprovider"
+)
+
+var (
+	socket  = flag.String("socket", "/run/docker/plugins/secret-file.sock", "path of the plugin socket")
+	file    = flag.String("file", "/var/lib/secret-file-driver/secrets", "path of the encrypted secrets file")
+	keyFile = flag.String("key", "/var/lib/secret-file-driver/key", "path of the key of the secrets file, generated if missing")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [OPTIONS] [set NAME]\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	key, err := loadKey(*keyFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch flag.NArg() {
+	case 0:
+		if err := serve(key); err != nil {
+			log.Fatal(err)
+		}
+	case 2:
+		if flag.Arg(0) != "set" {
+			flag.Usage()
+			os.Exit(1)
+		}
+		if err := set(key, flag.Arg(1)); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		flag.Usage()
+		os.Exit(1)
+	}
+}
+
+func serve(key []byte) error {
+	d, err := secretprovider.NewFileDriver(*file, key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(*socket), 0755); err != nil {
+		return err
+	}
+	os.Remove(*socket)
+	l, err := net.Listen("unix", *socket)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+	return http.Serve(l, secretprovider.NewHandler(d))
+}
+
+func set(key []byte, name string) error {
+	value, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		return err
+	}
+	secrets, err := secretprovider.ReadFile(*file, key)
+	if err != nil {
+		return err
+	}
+	secrets[name] = value
+	return secretprovider.WriteFile(*file, key, secrets)
+}
+
+func loadKey(path string) ([]byte, error) {
+	key, err := ioutil.ReadFile(path)
+	if err == nil || !os.IsNotExist(err) {
+		return key, err
+	}
+	key = make([]byte, secretprovider.KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	return key, ioutil.WriteFile(path, key, 0600)
+}
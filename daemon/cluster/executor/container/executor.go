@@ -84,6 +84,8 @@ func (e *executor) Describe(ctx context.Context) (*api.NodeDescription, error) {
 					plgnTyp = "Network"
 				case "logdriver":
 					plgnTyp = "Log"
+				case "secretprovider":
+					plgnTyp = "Secret"
 				}
 
 				plugins[api.PluginDescription{
@@ -12,6 +12,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	daemondiscovery "github.com/ellcrys/docker/daemon/discovery"
 	"github.com/ellcrys/docker/opts"
@@ -35,6 +36,14 @@ const (
 	// StockRuntimeName is the reserved name/alias used to represent the
 	// OCI runtime being shipped with the docker daemon package.
 	StockRuntimeName = "runc"
+	// DefaultEventsJournalMaxSize is the default maximum size of the events journal
+	DefaultEventsJournalMaxSize = int64(100 * 1024 * 1024)
+	// DefaultEventsJournalMaxAge is the default age after which events are
+	// removed from the events journal
+	DefaultEventsJournalMaxAge = "168h"
+	// DefaultImageGCInterval is the default time between two checks of the
+	// disk usage by the image garbage collector
+	DefaultImageGCInterval = "1m"
 	// DefaultShmSize is the default value for container's shm size
 	DefaultShmSize = int64(67108864)
 	// DefaultNetworkMtu is the default value for network MTU
@@ -145,10 +154,53 @@ type CommonConfig struct {
 	// may take place at a time for each push.
 	MaxConcurrentUploads *int `json:"max-concurrent-uploads,omitempty"`
 
+	// MaxDownloadChunks is the maximum number of parallel ranged requests
+	// a single large layer is downloaded with on pull.
+	MaxDownloadChunks int `json:"max-download-chunks,omitempty"`
+
+	// LayerCompression is the compression algorithm ("gzip" or "zstd")
+	// used for layer blobs uploaded on push.
+	LayerCompression string `json:"layer-compression,omitempty"`
+
+	// SignaturePolicy is the path of the signature policy file the images
+	// are verified against on pull and on container create. The signatures
+	// are read from the "signatures" directory of the daemon root.
+	SignaturePolicy string `json:"signature-policy,omitempty"`
+
 	// ShutdownTimeout is the timeout value (in seconds) the daemon will wait for the container
 	// to stop when daemon is being shutdown
 	ShutdownTimeout int `json:"shutdown-timeout,omitempty"`
 
+	// EventsJournal enables persisting events to disk, so that past events
+	// can be queried after a daemon restart.
+	EventsJournal bool `json:"events-journal,omitempty"`
+
+	// EventsJournalMaxSize is the maximum size of the events journal.
+	EventsJournalMaxSize opts.MemBytes `json:"events-journal-max-size,omitempty"`
+
+	// EventsJournalMaxAge is the age after which events are removed from the
+	// events journal, as a duration string such as "168h".
+	EventsJournalMaxAge string `json:"events-journal-max-age,omitempty"`
+
+	// ImageGCHighWatermark is the disk usage, in percent, of the filesystem
+	// holding the images over which unused images are removed, least
+	// recently used first. Zero disables the image garbage collector.
+	ImageGCHighWatermark int `json:"image-gc-high-watermark,omitempty"`
+
+	// ImageGCLowWatermark is the disk usage, in percent, under which the
+	// image garbage collector stops removing images. Defaults to ten points
+	// under ImageGCHighWatermark.
+	ImageGCLowWatermark int `json:"image-gc-low-watermark,omitempty"`
+
+	// ImageGCInterval is the time between two checks of the disk usage by
+	// the image garbage collector, as a duration string such as "1m".
+	ImageGCInterval string `json:"image-gc-interval,omitempty"`
+
+	// ImageGCProtect lists the rules matching the images which are never
+	// removed by the image garbage collector, as "label=<key>[=<value>]" or
+	// "reference=<pattern>".
+	ImageGCProtect []string `json:"image-gc-protect,omitempty"`
+
 	Debug     bool     `json:"debug,omitempty"`
 	Hosts     []string `json:"hosts,omitempty"`
 	LogLevel  string   `json:"log-level,omitempty"`
@@ -528,6 +580,42 @@ func Validate(config *Config) error {
 	if config.MaxConcurrentUploads != nil && *config.MaxConcurrentUploads < 0 {
 		return fmt.Errorf("invalid max concurrent uploads: %d", *config.MaxConcurrentUploads)
 	}
+	// validate MaxDownloadChunks
+	if config.MaxDownloadChunks < 0 {
+		return fmt.Errorf("invalid max download chunks: %d", config.MaxDownloadChunks)
+	}
+	// validate EventsJournalMaxAge
+	if config.EventsJournalMaxAge != "" {
+		if age, err := time.ParseDuration(config.EventsJournalMaxAge); err != nil || age < 0 {
+			return fmt.Errorf("invalid events journal max age: %s", config.EventsJournalMaxAge)
+		}
+	}
+	if config.EventsJournalMaxSize < 0 {
+		return fmt.Errorf("invalid events journal max size: %d", config.EventsJournalMaxSize)
+	}
+	// validate image gc options
+	if config.ImageGCHighWatermark < 0 || config.ImageGCHighWatermark > 100 {
+		return fmt.Errorf("invalid image gc high watermark: %d", config.ImageGCHighWatermark)
+	}
+	if config.ImageGCLowWatermark < 0 || (config.ImageGCHighWatermark > 0 && config.ImageGCLowWatermark >= config.ImageGCHighWatermark) {
+		return fmt.Errorf("invalid image gc low watermark: %d, it must be lower than the high watermark", config.ImageGCLowWatermark)
+	}
+	if config.ImageGCInterval != "" {
+		if interval, err := time.ParseDuration(config.ImageGCInterval); err != nil || interval <= 0 {
+			return fmt.Errorf("invalid image gc interval: %s", config.ImageGCInterval)
+		}
+	}
+	for _, rule := range config.ImageGCProtect {
+		if !strings.HasPrefix(rule, "label=") && !strings.HasPrefix(rule, "reference=") {
+			return fmt.Errorf("invalid image gc protection rule: %s", rule)
+		}
+	}
+	// validate LayerCompression
+	switch config.LayerCompression {
+	case "", "gzip", "zstd":
+	default:
+		return fmt.Errorf("invalid layer compression: %s", config.LayerCompression)
+	}
 
 	// validate that "default" runtime is not reset
 	if runtimes := config.GetAllRuntimes(); len(runtimes) > 0 {
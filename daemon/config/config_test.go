@@ -280,6 +280,13 @@ func TestValidateConfigurationErrors(t *testing.T) {
 				},
 			},
 		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					MaxDownloadChunks: -1,
+				},
+			},
+		},
 		{
 			config: &Config{
 				CommonConfig: CommonConfig{
@@ -305,6 +312,21 @@ func TestValidateConfigurationErrors(t *testing.T) {
 				},
 			},
 		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					ImageGCHighWatermark: 80,
+					ImageGCLowWatermark:  90,
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					ImageGCProtect: []string{"name=foo"},
+				},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		err := Validate(tc.config)
@@ -376,6 +398,16 @@ func TestValidateConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					ImageGCHighWatermark: 90,
+					ImageGCLowWatermark:  75,
+					ImageGCInterval:      "30s",
+					ImageGCProtect:       []string{"label=keep", "reference=alpine:*"},
+				},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		err := Validate(tc.config)
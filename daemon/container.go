@@ -284,6 +284,10 @@ func (daemon *Daemon) verifyContainerSettings(platform string, hostConfig *conta
 			if config.Healthcheck.StartPeriod != 0 && config.Healthcheck.StartPeriod < containertypes.MinimumDuration {
 				return nil, errors.Errorf("StartPeriod in Healthcheck cannot be less than %s", containertypes.MinimumDuration)
 			}
+
+			if err := validateHealthcheckTest(config.Healthcheck.Test); err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -329,7 +333,7 @@ func (daemon *Daemon) verifyContainerSettings(platform string, hostConfig *conta
 		if p.MaximumRetryCount != 0 {
 			return nil, errors.Errorf("maximum retry count cannot be used with restart policy '%s'", p.Name)
 		}
-	case "on-failure":
+	case "on-failure", "on-unhealthy":
 		if p.MaximumRetryCount < 0 {
 			return nil, errors.Errorf("maximum retry count cannot be negative")
 		}
@@ -339,6 +343,19 @@ func (daemon *Daemon) verifyContainerSettings(platform string, hostConfig *conta
 		return nil, errors.Errorf("invalid restart policy '%s'", p.Name)
 	}
 
+	if p.UnhealthyRetries != 0 && !p.IsOnUnhealthy() {
+		return nil, errors.Errorf("unhealthy retries cannot be used with restart policy '%s'", p.Name)
+	}
+	if p.UnhealthyRetries < 0 {
+		return nil, errors.Errorf("unhealthy retries cannot be negative")
+	}
+	if p.InitialBackoff < 0 || p.MaxBackoff < 0 || p.BackoffResetWindow < 0 {
+		return nil, errors.Errorf("restart backoff durations cannot be negative")
+	}
+	if p.InitialBackoff != 0 && p.MaxBackoff != 0 && p.MaxBackoff < p.InitialBackoff {
+		return nil, errors.Errorf("maximum restart backoff cannot be less than the initial backoff")
+	}
+
 	if !hostConfig.Isolation.IsValid() {
 		return nil, errors.Errorf("invalid isolation '%s' on %s", hostConfig.Isolation, runtime.GOOS)
 	}
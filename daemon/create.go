@@ -108,6 +108,10 @@ func (daemon *Daemon) create(params types.ContainerCreateConfig, managed bool) (
 		if runtime.GOOS == "windows" && img.OS == "linux" && !system.LCOWSupported() {
 			return nil, errors.New("operating system on which parent image was created is not Windows")
 		}
+
+		if err := daemon.imageService.VerifyImageSignature(params.Config.Image, img); err != nil {
+			return nil, err
+		}
 	} else {
 		if runtime.GOOS == "windows" {
 			os = "linux" // 'scratch' case.
@@ -273,6 +277,53 @@ func (daemon *Daemon) VolumeCreate(name, driverName string, opts, labels map[str
 	return apiV, nil
 }
 
+// VolumeSnapshot creates a volume with the given name and labels from a
+// snapshot of the volume source, taken by its driver.
+func (daemon *Daemon) VolumeSnapshot(source, name string, labels map[string]string) (*types.Volume, error) {
+	if name == "" {
+		name = stringid.GenerateNonCryptoID()
+	}
+
+	v, err := daemon.volumes.Snapshot(name, source, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	daemon.LogVolumeEvent(v.Name(), "create", map[string]string{"driver": v.DriverName(), "snapshot-of": source})
+	apiV := volumeToAPIType(v)
+	apiV.Mountpoint = v.Path()
+	return apiV, nil
+}
+
+// VolumeClone creates a volume with the given name and labels from a copy of
+// the volume source, made by its driver. If driverName is set, it must be
+// the driver of the volume source.
+func (daemon *Daemon) VolumeClone(source, name, driverName string, labels map[string]string) (*types.Volume, error) {
+	if name == "" {
+		name = stringid.GenerateNonCryptoID()
+	}
+
+	if driverName != "" {
+		src, err := daemon.volumes.Get(source)
+		if err != nil {
+			return nil, err
+		}
+		if src.DriverName() != driverName {
+			return nil, errdefs.InvalidParameter(fmt.Errorf("volume %s was created by driver %s, not %s", source, src.DriverName(), driverName))
+		}
+	}
+
+	v, err := daemon.volumes.Clone(name, source, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	daemon.LogVolumeEvent(v.Name(), "create", map[string]string{"driver": v.DriverName(), "from": source})
+	apiV := volumeToAPIType(v)
+	apiV.Mountpoint = v.Path()
+	return apiV, nil
+}
+
 func (daemon *Daemon) mergeAndVerifyConfig(config *containertypes.Config, img *image.Image) error {
 	if img != nil && img.Config != nil {
 		if err := merge(config, img.Config); err != nil {
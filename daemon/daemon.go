@@ -36,11 +36,14 @@ import (
 	_ "github.com/ellcrys/docker/daemon/graphdriver/register"
 	"github.com/ellcrys/docker/daemon/stats"
 	dmetadata "github.com/ellcrys/docker/distribution/metadata"
+	"github.com/ellcrys/docker/distribution/xfer"
 	"github.com/ellcrys/docker/dockerversion"
 	"github.com/ellcrys/docker/image"
+	"github.com/ellcrys/docker/image/signature"
 	"github.com/ellcrys/docker/layer"
 	"github.com/ellcrys/docker/libcontainerd"
 	"github.com/ellcrys/docker/migrate/v1"
+	"github.com/ellcrys/docker/pkg/archive"
 	"github.com/ellcrys/docker/pkg/idtools"
 	"github.com/ellcrys/docker/pkg/locker"
 	"github.com/ellcrys/docker/pkg/plugingetter"
@@ -64,6 +67,10 @@ import (
 // ContainersNamespace is the name of the namespace used for users containers
 const ContainersNamespace = "moby"
 
+// volumeUsageInterval is the interval at which the usage of the volumes of
+// the local driver is measured in the background.
+const volumeUsageInterval = 15 * time.Minute
+
 var (
 	errSystemNotSupported = errors.New("the Docker daemon is not supported on this platform")
 )
@@ -822,6 +829,11 @@ func NewDaemon(config *config.Config, registryService registry.Service, containe
 		return nil, err
 	}
 
+	partialDownloads, err := xfer.NewPartialDownloads(filepath.Join(imageRoot, "downloads"))
+	if err != nil {
+		return nil, err
+	}
+
 	// No content-addressability migration on Windows as it never supported pre-CA
 	if runtime.GOOS != "windows" {
 		migrationStart := time.Now()
@@ -854,7 +866,19 @@ func NewDaemon(config *config.Config, registryService registry.Service, containe
 	d.idIndex = truncindex.NewTruncIndex([]string{})
 	d.statsCollector = d.newStatsCollector(1 * time.Second)
 
-	d.EventsService = events.New()
+	if config.EventsJournal {
+		maxAge, err := time.ParseDuration(config.EventsJournalMaxAge)
+		if config.EventsJournalMaxAge != "" && err != nil {
+			return nil, err
+		}
+		journal, err := events.OpenJournal(filepath.Join(config.Root, "events"), config.EventsJournalMaxSize.Value(), maxAge)
+		if err != nil {
+			return nil, err
+		}
+		d.EventsService = events.NewWithJournal(journal)
+	} else {
+		d.EventsService = events.New()
+	}
 	d.volumes = volStore
 	d.root = config.Root
 	d.idMappings = idMappings
@@ -863,6 +887,19 @@ func NewDaemon(config *config.Config, registryService registry.Service, containe
 
 	d.linkIndex = newLinkIndex()
 
+	layerCompression := archive.Gzip
+	if config.LayerCompression == "zstd" {
+		layerCompression = archive.Zstd
+	}
+
+	var signaturePolicy *signature.Policy
+	if config.SignaturePolicy != "" {
+		signaturePolicy, err = signature.LoadPolicy(config.SignaturePolicy, signature.NewStore(filepath.Join(config.Root, "signatures")))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// TODO: imageStore, distributionMetadataStore, and ReferenceStore are only
 	// used above to run migration. They could be initialized in ImageService
 	// if migration is called from daemon/images. layerStore might move as well.
@@ -871,14 +908,24 @@ func NewDaemon(config *config.Config, registryService registry.Service, containe
 		DistributionMetadataStore: distributionMetadataStore,
 		EventsService:             d.EventsService,
 		ImageStore:                imageStore,
+		LayerCompression:          layerCompression,
 		LayerStores:               layerStores,
 		MaxConcurrentDownloads:    *config.MaxConcurrentDownloads,
 		MaxConcurrentUploads:      *config.MaxConcurrentUploads,
+		MaxDownloadChunks:         config.MaxDownloadChunks,
+		PartialDownloads:          partialDownloads,
 		ReferenceStore:            rs,
 		RegistryService:           registryService,
+		SignaturePolicy:           signaturePolicy,
 		TrustKey:                  trustKey,
 	})
 
+	if config.ImageGCHighWatermark > 0 {
+		if err := d.startImageGC(config); err != nil {
+			return nil, err
+		}
+	}
+
 	go d.execCommandGC()
 
 	d.containerd, err = containerdRemote.NewClient(ContainersNamespace, d)
@@ -944,6 +991,29 @@ func (daemon *Daemon) shutdownContainer(c *container.Container) error {
 	return nil
 }
 
+// startImageGC starts removing unused images once the disk holding the
+// storage driver's data goes over the configured high watermark.
+func (daemon *Daemon) startImageGC(config *config.Config) error {
+	interval := time.Minute
+	if config.ImageGCInterval != "" {
+		var err error
+		if interval, err = time.ParseDuration(config.ImageGCInterval); err != nil {
+			return err
+		}
+	}
+	lowWatermark := config.ImageGCLowWatermark
+	if lowWatermark == 0 && config.ImageGCHighWatermark > 10 {
+		lowWatermark = config.ImageGCHighWatermark - 10
+	}
+	return daemon.imageService.StartImageGC(images.ImageGCPolicy{
+		Root:          filepath.Join(config.Root, daemon.graphDrivers[runtime.GOOS]),
+		HighWatermark: config.ImageGCHighWatermark,
+		LowWatermark:  lowWatermark,
+		Interval:      interval,
+		Protect:       config.ImageGCProtect,
+	})
+}
+
 // ShutdownTimeout returns the timeout (in seconds) before containers are forcibly
 // killed during shutdown. The default timeout can be configured both on the daemon
 // and per container, and the longest timeout will be used. A grace-period of
@@ -1033,6 +1103,12 @@ func (daemon *Daemon) Shutdown() error {
 		daemon.netController.Stop()
 	}
 
+	if daemon.EventsService != nil {
+		if err := daemon.EventsService.Close(); err != nil {
+			logrus.Errorf("Error closing events journal: %v", err)
+		}
+	}
+
 	return daemon.cleanupMounts()
 }
 
@@ -1153,7 +1229,12 @@ func (daemon *Daemon) configureVolumes(rootIDs idtools.IDPair) (*store.VolumeSto
 	if !drivers.Register(volumeDriver, volumeDriver.Name()) {
 		return nil, errors.New("local volume driver could not be registered")
 	}
-	return store.New(daemon.configStore.Root, drivers)
+	volStore, err := store.New(daemon.configStore.Root, drivers)
+	if err != nil {
+		return nil, err
+	}
+	volStore.StartUsageMonitor(volumeUsageInterval)
+	return volStore, nil
 }
 
 // IsShuttingDown tells whether the daemon is shutting down or not
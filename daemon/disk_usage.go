@@ -7,7 +7,7 @@ import (
 
 	"github.com/ellcrys/docker/api/types"
 	"github.com/ellcrys/docker/api/types/filters"
-	"github.com/ellcrys/docker/pkg/directory"
+	"github.com/ellcrys/docker/errdefs"
 	"github.com/ellcrys/docker/volume"
 	"github.com/sirupsen/logrus"
 )
@@ -46,24 +46,26 @@ func (daemon *Daemon) SystemDiskUsage(ctx context.Context) (*types.DiskUsage, er
 			return nil, ctx.Err()
 		default:
 		}
-		if d, ok := v.(volume.DetailedVolume); ok {
-			if len(d.Options()) > 0 {
-				// skip local volumes with mount options since these could have external
-				// mounted filesystems that will be slow to enumerate.
-				continue
-			}
+		u, err := daemon.volumes.Usage(ctx, v)
+		if errdefs.IsNotImplemented(err) {
+			// skip local volumes with mount options since these could have external
+			// mounted filesystems that will be slow to enumerate.
+			continue
 		}
 
 		name := v.Name()
 		refs := daemon.volumes.Refs(v)
 
 		tv := volumeToAPIType(v)
-		sz, err := directory.Size(ctx, v.Path())
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
 			logrus.Warnf("failed to determine size of volume %v", name)
-			sz = -1
+			tv.UsageData = &types.VolumeUsageData{Size: -1, RefCount: int64(len(refs))}
+		} else {
+			tv.UsageData = volumeUsageToAPIType(u, len(refs))
 		}
-		tv.UsageData = &types.VolumeUsageData{Size: sz, RefCount: int64(len(refs))}
 		allVolumes = append(allVolumes, tv)
 	}
 
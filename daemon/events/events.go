@@ -6,6 +6,7 @@ import (
 
 	eventtypes "github.com/ellcrys/docker/api/types/events"
 	"github.com/ellcrys/docker/pkg/pubsub"
+	"github.com/sirupsen/logrus"
 )
 
 const (
@@ -15,9 +16,10 @@ const (
 
 // Events is pubsub channel for events generated by the engine.
 type Events struct {
-	mu     sync.Mutex
-	events []eventtypes.Message
-	pub    *pubsub.Publisher
+	mu      sync.Mutex
+	events  []eventtypes.Message
+	pub     *pubsub.Publisher
+	journal *Journal
 }
 
 // New returns new *Events instance
@@ -28,6 +30,14 @@ func New() *Events {
 	}
 }
 
+// NewWithJournal returns new *Events instance which persists events to the
+// journal, and serves queries for past events from it.
+func NewWithJournal(journal *Journal) *Events {
+	e := New()
+	e.journal = journal
+	return e
+}
+
 // Subscribe adds new listener to events, returns slice of 256 stored
 // last events, a channel in which you can expect new events (in form
 // of interface{}, so you need type assertion), and a function to call
@@ -47,8 +57,9 @@ func (e *Events) Subscribe() ([]eventtypes.Message, chan interface{}, func()) {
 }
 
 // SubscribeTopic adds new listener to events, returns slice of 256 stored
-// last events, a channel in which you can expect new events (in form
-// of interface{}, so you need type assertion).
+// last events (or of all journaled events, if a journal is used), a channel
+// in which you can expect new events (in form of interface{}, so you need
+// type assertion).
 func (e *Events) SubscribeTopic(since, until time.Time, ef *Filter) ([]eventtypes.Message, chan interface{}) {
 	eventSubscribers.Inc()
 	e.mu.Lock()
@@ -110,6 +121,11 @@ func (e *Events) PublishMessage(jm eventtypes.Message) {
 	eventsCounter.Inc()
 
 	e.mu.Lock()
+	if e.journal != nil {
+		if err := e.journal.Write(jm); err != nil {
+			logrus.WithError(err).Warn("Failed to write event to the journal")
+		}
+	}
 	if len(e.events) == cap(e.events) {
 		// discard oldest event
 		copy(e.events, e.events[1:])
@@ -121,6 +137,14 @@ func (e *Events) PublishMessage(jm eventtypes.Message) {
 	e.pub.Publish(jm)
 }
 
+// Close closes the events journal, if any.
+func (e *Events) Close() error {
+	if e.journal == nil {
+		return nil
+	}
+	return e.journal.Close()
+}
+
 // SubscribersCount returns number of event listeners
 func (e *Events) SubscribersCount() int {
 	return e.pub.Len()
@@ -136,6 +160,14 @@ func (e *Events) loadBufferedEvents(since, until time.Time, topic func(interface
 		return buffered
 	}
 
+	if e.journal != nil {
+		journaled, err := e.journal.Read(since, until, topic)
+		if err == nil {
+			return journaled
+		}
+		logrus.WithError(err).Warn("Failed to read events from the journal, falling back to buffered events")
+	}
+
 	var sinceNanoUnix int64
 	if !since.IsZero() {
 		sinceNanoUnix = since.UnixNano()
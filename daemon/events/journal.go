@@ -0,0 +1,270 @@
+package events // import "github.com/ellcrys/docker/daemon/events"
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	eventtypes "github.com/ellcrys/docker/api/types/events"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	journalSegmentExt = ".log"
+	// number of segments the journal size limit is spread over
+	journalSegments = 10
+	// smallest size a segment is allowed to grow to before being rotated
+	minJournalSegmentSize = 64 * 1024
+	// longest line accepted when reading back a segment
+	maxJournalLineSize = 1024 * 1024
+)
+
+// Journal persists events to disk, so that they can be queried after they
+// have been dropped from the in-memory buffer, or after a daemon restart.
+//
+// Events are appended as JSON lines to segment files, named after the time
+// of their first event. The oldest segments are removed once the journal
+// grows over its size limit, or once all their events are older than the
+// journal's maximum age.
+type Journal struct {
+	mu          sync.Mutex
+	root        string
+	maxSize     int64
+	maxAge      time.Duration
+	segmentSize int64
+	segments    []*journalSegment
+	current     *os.File
+}
+
+type journalSegment struct {
+	path  string
+	first int64 // TimeNano of the first event in the segment
+	last  int64 // TimeNano of the last event in the segment
+	size  int64
+}
+
+// OpenJournal opens the journal stored in root, creating it if needed.
+// A maxSize or maxAge of zero means no limit.
+func OpenJournal(root string, maxSize int64, maxAge time.Duration) (*Journal, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, errors.Wrap(err, "error creating events journal directory")
+	}
+
+	segmentSize := maxSize / journalSegments
+	if segmentSize < minJournalSegmentSize {
+		segmentSize = minJournalSegmentSize
+	}
+	j := &Journal{
+		root:        root,
+		maxSize:     maxSize,
+		maxAge:      maxAge,
+		segmentSize: segmentSize,
+	}
+
+	files, err := ioutil.ReadDir(root)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading events journal directory")
+	}
+	for _, fi := range files {
+		name := fi.Name()
+		if fi.IsDir() || !strings.HasSuffix(name, journalSegmentExt) {
+			continue
+		}
+		first, err := strconv.ParseInt(strings.TrimSuffix(name, journalSegmentExt), 10, 64)
+		if err != nil {
+			logrus.Warnf("Ignoring unexpected file in events journal: %s", name)
+			continue
+		}
+		j.segments = append(j.segments, &journalSegment{
+			path:  filepath.Join(root, name),
+			first: first,
+			// segments are append-only, so they were last written to
+			// when their last event was
+			last: fi.ModTime().UnixNano(),
+			size: fi.Size(),
+		})
+	}
+	sort.Slice(j.segments, func(i, k int) bool { return j.segments[i].first < j.segments[k].first })
+
+	j.mu.Lock()
+	j.prune(time.Now())
+	j.mu.Unlock()
+	return j, nil
+}
+
+// Write appends an event to the journal.
+func (j *Journal) Write(ev eventtypes.Message) error {
+	line, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	// A new segment is always started after a restart, so that a line left
+	// incomplete by a crash is never continued.
+	if j.current == nil || j.segments[len(j.segments)-1].size+int64(len(line)) > j.segmentSize {
+		if err := j.rotate(ev.TimeNano); err != nil {
+			return err
+		}
+	}
+
+	n, err := j.current.Write(line)
+	current := j.segments[len(j.segments)-1]
+	current.size += int64(n)
+	if ev.TimeNano > current.last {
+		current.last = ev.TimeNano
+	}
+	if err != nil {
+		return errors.Wrap(err, "error writing to events journal")
+	}
+	return nil
+}
+
+// rotate starts a new segment, and removes segments over the journal's
+// limits. Must be called with j.mu held.
+func (j *Journal) rotate(first int64) error {
+	if j.current != nil {
+		j.current.Close()
+		j.current = nil
+	}
+	if n := len(j.segments); n > 0 && j.segments[n-1].first >= first {
+		// keep segment names unique and ordered even if the clock went back
+		first = j.segments[n-1].first + 1
+	}
+
+	path := filepath.Join(j.root, fmt.Sprintf("%020d%s", first, journalSegmentExt))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return errors.Wrap(err, "error creating events journal segment")
+	}
+	j.current = f
+	j.segments = append(j.segments, &journalSegment{path: path, first: first, last: first})
+	j.prune(time.Now())
+	return nil
+}
+
+// prune removes the oldest segments while the journal is over its size
+// limit, or while they only hold events older than the journal's maximum
+// age. The most recent segment is never removed.
+// Must be called with j.mu held.
+func (j *Journal) prune(now time.Time) {
+	var total int64
+	for _, s := range j.segments {
+		total += s.size
+	}
+
+	var cutoff int64
+	if j.maxAge > 0 {
+		cutoff = now.Add(-j.maxAge).UnixNano()
+	}
+
+	for len(j.segments) > 1 {
+		oldest := j.segments[0]
+		overSize := j.maxSize > 0 && total > j.maxSize
+		expired := cutoff > 0 && oldest.last < cutoff
+		if !overSize && !expired {
+			break
+		}
+		if err := os.Remove(oldest.path); err != nil && !os.IsNotExist(err) {
+			logrus.WithError(err).Warnf("Failed to remove events journal segment %s", oldest.path)
+			break
+		}
+		total -= oldest.size
+		j.segments = j.segments[1:]
+	}
+}
+
+// Read returns the journaled events emitted between since and until, which
+// match topic. A zero until means no upper bound. A nil topic matches all
+// events.
+func (j *Journal) Read(since, until time.Time, topic func(interface{}) bool) ([]eventtypes.Message, error) {
+	var sinceNano, untilNano int64
+	if !since.IsZero() {
+		sinceNano = since.UnixNano()
+	}
+	if !until.IsZero() {
+		untilNano = until.UnixNano()
+	}
+
+	j.mu.Lock()
+	segments := make([]journalSegment, len(j.segments))
+	for i, s := range j.segments {
+		segments[i] = *s
+	}
+	j.mu.Unlock()
+
+	var events []eventtypes.Message
+	for _, s := range segments {
+		if s.last < sinceNano {
+			// all events of this segment were emitted before since
+			continue
+		}
+		if untilNano > 0 && s.first > untilNano {
+			break
+		}
+		read, err := readJournalSegment(s.path, sinceNano, untilNano, topic)
+		if err != nil {
+			if os.IsNotExist(errors.Cause(err)) {
+				// pruned while reading
+				continue
+			}
+			return nil, err
+		}
+		events = append(events, read...)
+	}
+	return events, nil
+}
+
+func readJournalSegment(path string, sinceNano, untilNano int64, topic func(interface{}) bool) ([]eventtypes.Message, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error opening events journal segment")
+	}
+	defer f.Close()
+
+	var events []eventtypes.Message
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJournalLineSize)
+	for scanner.Scan() {
+		var ev eventtypes.Message
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			// most likely a line left incomplete by a crash
+			logrus.WithError(err).Debugf("Skipping invalid entry in events journal segment %s", path)
+			continue
+		}
+		if ev.TimeNano < sinceNano || (untilNano > 0 && ev.TimeNano > untilNano) {
+			continue
+		}
+		if topic == nil || topic(ev) {
+			events = append(events, ev)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "error reading events journal segment")
+	}
+	return events, nil
+}
+
+// Close closes the journal.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.current == nil {
+		return nil
+	}
+	err := j.current.Close()
+	j.current = nil
+	return err
+}
@@ -0,0 +1,109 @@
+package events // import "github.com/ellcrys/docker/daemon/events"
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ellcrys/docker/api/types/events"
+	"github.com/ellcrys/docker/api/types/filters"
+	"github.com/gotestyourself/gotestyourself/assert"
+	is "github.com/gotestyourself/gotestyourself/assert/cmp"
+)
+
+func journalEvent(action string, t time.Time) events.Message {
+	return events.Message{
+		Action:   action,
+		Type:     events.ContainerEventType,
+		Actor:    events.Actor{ID: "cont"},
+		Scope:    "local",
+		Time:     t.Unix(),
+		TimeNano: t.UnixNano(),
+	}
+}
+
+func TestJournalReadSurvivesReopen(t *testing.T) {
+	root, err := ioutil.TempDir("", "events-journal")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+
+	j, err := OpenJournal(root, 0, 0)
+	assert.NilError(t, err)
+
+	start := time.Now().Add(-time.Hour)
+	for i, action := range []string{"create", "start", "die", "destroy"} {
+		assert.NilError(t, j.Write(journalEvent(action, start.Add(time.Duration(i)*time.Minute))))
+	}
+	assert.NilError(t, j.Close())
+
+	j, err = OpenJournal(root, 0, 0)
+	assert.NilError(t, err)
+	defer j.Close()
+
+	evs, err := j.Read(start.Add(time.Minute), start.Add(2*time.Minute), nil)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(evs, 2))
+	assert.Check(t, is.Equal("start", evs[0].Action))
+	assert.Check(t, is.Equal("die", evs[1].Action))
+
+	// a new segment is started after reopening the journal
+	assert.NilError(t, j.Write(journalEvent("create", start.Add(10*time.Minute))))
+	assert.Check(t, is.Len(j.segments, 2))
+
+	evs, err = j.Read(start, time.Time{}, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(evs, 5))
+}
+
+func TestJournalPrune(t *testing.T) {
+	root, err := ioutil.TempDir("", "events-journal")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+
+	j, err := OpenJournal(root, 0, 24*time.Hour)
+	assert.NilError(t, err)
+	defer j.Close()
+
+	old := time.Now().Add(-48 * time.Hour)
+	assert.NilError(t, j.Write(journalEvent("create", old)))
+	assert.NilError(t, j.rotate(time.Now().UnixNano()))
+	assert.NilError(t, j.Write(journalEvent("start", time.Now())))
+	assert.NilError(t, j.rotate(time.Now().UnixNano()))
+
+	evs, err := j.Read(old.Add(-time.Second), time.Time{}, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(evs, 1))
+	assert.Check(t, is.Equal("start", evs[0].Action))
+
+	files, err := ioutil.ReadDir(root)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(files, 2))
+}
+
+func TestEventsWithJournalSubscribeTopic(t *testing.T) {
+	root, err := ioutil.TempDir("", "events-journal")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+
+	j, err := OpenJournal(root, 0, 0)
+	assert.NilError(t, err)
+	e := NewWithJournal(j)
+	defer e.Close()
+
+	since := time.Now().Add(-time.Second)
+	// more events than the in-memory buffer can hold
+	for i := 0; i < eventsLimit+10; i++ {
+		e.Log("create", events.ContainerEventType, events.Actor{ID: "cont"})
+	}
+	e.Log("create", events.ImageEventType, events.Actor{ID: "image"})
+
+	buffered, l := e.SubscribeTopic(since, time.Time{}, NewFilter(filters.NewArgs()))
+	defer e.Evict(l)
+	assert.Check(t, is.Len(buffered, eventsLimit+11))
+
+	buffered, l2 := e.SubscribeTopic(since, time.Time{}, NewFilter(filters.NewArgs(filters.Arg("type", "image"))))
+	defer e.Evict(l2)
+	assert.Assert(t, is.Len(buffered, 1))
+	assert.Check(t, is.Equal("image", buffered[0].Actor.ID))
+}
@@ -170,19 +170,18 @@ func (q *Control) SetQuota(targetPath string, quota Quota) error {
 	projectID, ok := q.quotas[targetPath]
 	if !ok {
 		projectID = q.nextProjectID
-
-		//
-		// assign project id to new container directory
-		//
-		err := setProjectID(targetPath, projectID)
-		if err != nil {
-			return err
-		}
-
-		q.quotas[targetPath] = projectID
 		q.nextProjectID++
 	}
 
+	//
+	// assign project id to the directory, also when it is already known, as
+	// the directory may have been removed and created again with the same path
+	//
+	if err := setProjectID(targetPath, projectID); err != nil {
+		return err
+	}
+	q.quotas[targetPath] = projectID
+
 	//
 	// set the quota limit for the container's project id
 	//
@@ -243,6 +242,31 @@ func (q *Control) GetQuota(targetPath string, quota *Quota) error {
 	return nil
 }
 
+// GetUsage - get the disk space used by a directory that was configured
+// with SetQuota
+func (q *Control) GetUsage(targetPath string) (uint64, error) {
+
+	projectID, ok := q.quotas[targetPath]
+	if !ok {
+		return 0, fmt.Errorf("quota not found for path : %s", targetPath)
+	}
+
+	var d C.fs_disk_quota_t
+
+	var cs = C.CString(q.backingFsBlockDev)
+	defer C.free(unsafe.Pointer(cs))
+
+	_, _, errno := unix.Syscall6(unix.SYS_QUOTACTL, C.Q_XGETPQUOTA,
+		uintptr(unsafe.Pointer(cs)), uintptr(C.__u32(projectID)),
+		uintptr(unsafe.Pointer(&d)), 0, 0)
+	if errno != 0 {
+		return 0, fmt.Errorf("Failed to get quota usage for projid %d on %s: %v",
+			projectID, q.backingFsBlockDev, errno.Error())
+	}
+
+	return uint64(d.d_bcount) * 512, nil
+}
+
 // getProjectID - get the project id of path on xfs
 func getProjectID(targetPath string) (uint32, error) {
 	dir, err := openDir(targetPath)
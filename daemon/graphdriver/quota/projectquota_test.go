@@ -58,6 +58,8 @@ func TestBlockDev(t *testing.T) {
 	t.Run("testSmallerThanQuota", wrapMountTest(imageFileName, true, wrapQuotaTest(testSmallerThanQuota)))
 	t.Run("testBiggerThanQuota", wrapMountTest(imageFileName, true, wrapQuotaTest(testBiggerThanQuota)))
 	t.Run("testRetrieveQuota", wrapMountTest(imageFileName, true, wrapQuotaTest(testRetrieveQuota)))
+	t.Run("testRetrieveUsage", wrapMountTest(imageFileName, true, wrapQuotaTest(testRetrieveUsage)))
+	t.Run("testRecreatedDir", wrapMountTest(imageFileName, true, wrapQuotaTest(testRecreatedDir)))
 }
 
 func wrapMountTest(imageFileName string, enableQuota bool, testFunc func(t *testing.T, mountPoint, backingFsDev string)) func(*testing.T) {
@@ -150,3 +152,27 @@ func testRetrieveQuota(t *testing.T, ctrl *Control, homeDir, testDir, testSubDir
 	assert.NilError(t, ctrl.GetQuota(testSubDir, &q))
 	assert.Check(t, is.Equal(uint64(testQuotaSize), q.Size))
 }
+
+func testRetrieveUsage(t *testing.T, ctrl *Control, homeDir, testDir, testSubDir string) {
+	// Validate that we can retrieve the space used under the quota
+	assert.NilError(t, ctrl.SetQuota(testSubDir, Quota{testQuotaSize}))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(testSubDir, "file"), make([]byte, testQuotaSize/2), 0644))
+	unix.Sync()
+
+	usage, err := ctrl.GetUsage(testSubDir)
+	assert.NilError(t, err)
+	assert.Check(t, usage >= testQuotaSize/2)
+}
+
+func testRecreatedDir(t *testing.T, ctrl *Control, homeDir, testDir, testSubDir string) {
+	// Make sure the quota is enforced on a directory created again with the
+	// same path
+	assert.NilError(t, ctrl.SetQuota(testSubDir, Quota{testQuotaSize}))
+	assert.NilError(t, os.RemoveAll(testSubDir))
+	assert.NilError(t, os.Mkdir(testSubDir, 0755))
+	assert.NilError(t, ctrl.SetQuota(testSubDir, Quota{testQuotaSize}))
+
+	biggerThanQuotaFile := filepath.Join(testSubDir, "bigger-than-quota")
+	err := ioutil.WriteFile(biggerThanQuotaFile, make([]byte, testQuotaSize+1), 0644)
+	assert.Assert(t, is.ErrorContains(err, ""))
+}
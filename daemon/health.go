@@ -3,10 +3,16 @@ package daemon // import "github.com/ellcrys/docker/daemon"
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
 	"runtime"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/ellcrys/docker/api/types"
@@ -14,7 +20,10 @@ import (
 	"github.com/ellcrys/docker/api/types/strslice"
 	"github.com/ellcrys/docker/container"
 	"github.com/ellcrys/docker/daemon/exec"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 const (
@@ -44,7 +53,8 @@ const (
 const (
 	// Exit status codes that can be returned by the probe command.
 
-	exitStatusHealthy = 0 // Container is healthy
+	exitStatusHealthy   = 0 // Container is healthy
+	exitStatusUnhealthy = 1 // Container is unhealthy
 )
 
 // probe implementations know how to run a particular type of probe.
@@ -114,6 +124,178 @@ func (p *cmdProbe) run(ctx context.Context, d *Daemon, cntr *container.Container
 	}, nil
 }
 
+// httpProbe implements the "HTTP" probe type.
+type httpProbe struct{}
+
+// Send a GET request to the configured URL from within the container's
+// network namespace. Any 2xx or 3xx response is considered healthy.
+func (p *httpProbe) run(ctx context.Context, d *Daemon, cntr *container.Container) (*types.HealthcheckResult, error) {
+	nsPath := sandboxKey(cntr)
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				return dialInNetNS(ctx, nsPath, network, address)
+			},
+			// The probe checks whether the service responds, not who it is.
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+			DisableKeepAlives: true,
+		},
+		// Redirects are reported as-is instead of being followed.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	req, err := http.NewRequest("GET", cntr.Config.Healthcheck.Test[1], nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return probeFailure(err), nil
+	}
+	defer resp.Body.Close()
+
+	output := &limitedBuffer{}
+	fmt.Fprintf(output, "HTTP %s\n", resp.Status)
+	io.Copy(output, io.LimitReader(resp.Body, maxOutputLen))
+
+	exitCode := exitStatusUnhealthy
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusBadRequest {
+		exitCode = exitStatusHealthy
+	}
+	return &types.HealthcheckResult{
+		End:      time.Now(),
+		ExitCode: exitCode,
+		Output:   output.String(),
+	}, nil
+}
+
+// tcpProbe implements the "TCP" probe type.
+type tcpProbe struct{}
+
+// Open a TCP connection to the configured address from within the
+// container's network namespace.
+func (p *tcpProbe) run(ctx context.Context, d *Daemon, cntr *container.Container) (*types.HealthcheckResult, error) {
+	address := cntr.Config.Healthcheck.Test[1]
+	conn, err := dialInNetNS(ctx, sandboxKey(cntr), "tcp", address)
+	if err != nil {
+		return probeFailure(err), nil
+	}
+	conn.Close()
+	return &types.HealthcheckResult{
+		End:      time.Now(),
+		ExitCode: exitStatusHealthy,
+		Output:   fmt.Sprintf("Connected to %s", address),
+	}, nil
+}
+
+// grpcProbe implements the "GRPC" probe type.
+type grpcProbe struct{}
+
+// Call the standard gRPC health checking service at the configured address
+// from within the container's network namespace. The service to check may be
+// given as an optional second argument.
+func (p *grpcProbe) run(ctx context.Context, d *Daemon, cntr *container.Container) (*types.HealthcheckResult, error) {
+	test := cntr.Config.Healthcheck.Test
+	var service string
+	if len(test) > 2 {
+		service = test[2]
+	}
+
+	nsPath := sandboxKey(cntr)
+	conn, err := grpc.DialContext(ctx, test[1],
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+		grpc.WithDialer(func(address string, timeout time.Duration) (net.Conn, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return dialInNetNS(ctx, nsPath, "tcp", address)
+		}),
+	)
+	if err != nil {
+		return probeFailure(err), nil
+	}
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		return probeFailure(err), nil
+	}
+
+	exitCode := exitStatusUnhealthy
+	if resp.Status == healthpb.HealthCheckResponse_SERVING {
+		exitCode = exitStatusHealthy
+	}
+	return &types.HealthcheckResult{
+		End:      time.Now(),
+		ExitCode: exitCode,
+		Output:   resp.Status.String(),
+	}, nil
+}
+
+// probeFailure returns the result of a network probe which could not reach
+// the container. Unlike an error returned from run, this counts as a regular
+// unhealthy result.
+func probeFailure(err error) *types.HealthcheckResult {
+	return &types.HealthcheckResult{
+		End:      time.Now(),
+		ExitCode: exitStatusUnhealthy,
+		Output:   err.Error(),
+	}
+}
+
+// sandboxKey returns the path of the container's network namespace, or an
+// empty string if the container has none.
+func sandboxKey(cntr *container.Container) string {
+	cntr.Lock()
+	defer cntr.Unlock()
+	if cntr.NetworkSettings == nil {
+		return ""
+	}
+	return cntr.NetworkSettings.SandboxKey
+}
+
+// validateHealthcheckTest validates the arguments of the network probe types.
+// Other probe types are not checked here, to keep accepting configurations
+// that are ignored at runtime.
+func validateHealthcheckTest(test []string) error {
+	if len(test) == 0 {
+		return nil
+	}
+	switch test[0] {
+	case "HTTP":
+		if len(test) != 2 {
+			return errors.New("HTTP healthcheck requires exactly one URL")
+		}
+		u, err := url.Parse(test[1])
+		if err != nil {
+			return errors.Wrap(err, "invalid URL in HTTP healthcheck")
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return errors.Errorf("invalid URL in HTTP healthcheck: unsupported scheme %q", u.Scheme)
+		}
+		if u.Host == "" {
+			return errors.Errorf("invalid URL in HTTP healthcheck: missing host")
+		}
+	case "TCP":
+		if len(test) != 2 {
+			return errors.New("TCP healthcheck requires exactly one address")
+		}
+		if _, _, err := net.SplitHostPort(test[1]); err != nil {
+			return errors.Wrap(err, "invalid address in TCP healthcheck")
+		}
+	case "GRPC":
+		if len(test) != 2 && len(test) != 3 {
+			return errors.New("GRPC healthcheck requires an address and an optional service name")
+		}
+		if _, _, err := net.SplitHostPort(test[1]); err != nil {
+			return errors.Wrap(err, "invalid address in GRPC healthcheck")
+		}
+	}
+	return nil
+}
+
 // Update the container's Status.Health struct based on the latest probe's result.
 func handleProbeResult(d *Daemon, c *container.Container, result *types.HealthcheckResult, done chan struct{}) {
 	c.Lock()
@@ -180,6 +362,31 @@ func handleProbeResult(d *Daemon, c *container.Container, result *types.Healthch
 	if oldStatus != current {
 		d.LogContainerEvent(c, "health_status: "+current)
 	}
+
+	if c.HostConfig != nil && c.HostConfig.RestartPolicy.IsOnUnhealthy() &&
+		current == types.Unhealthy && h.FailingStreak >= c.HostConfig.RestartPolicy.UnhealthyRetries {
+		d.killUnhealthy(c)
+	}
+}
+
+// killUnhealthy kills a container found unhealthy, so that its "on-unhealthy"
+// restart policy restarts it. Unlike a user-requested kill, this does not
+// cancel the container's restart manager.
+// Called with c locked.
+func (d *Daemon) killUnhealthy(c *container.Container) {
+	logrus.Infof("Killing container %s after %d failed healthchecks", c.ID, c.State.Health.FailingStreak)
+	// Healthchecks are started again once the container is restarted.
+	d.stopHealthchecks(c)
+	if c.Restarting {
+		return
+	}
+	if err := d.kill(c, int(syscall.SIGKILL)); err != nil {
+		logrus.WithError(err).Warnf("Failed to kill unhealthy container %s", c.ID)
+		return
+	}
+	d.LogContainerEventWithAttributes(c, "kill", map[string]string{
+		"signal": fmt.Sprintf("%d", syscall.SIGKILL),
+	})
 }
 
 // Run the container's monitoring thread until notified via "stop".
@@ -257,6 +464,12 @@ func getProbe(c *container.Container) probe {
 		return &cmdProbe{shell: false}
 	case "CMD-SHELL":
 		return &cmdProbe{shell: true}
+	case "HTTP":
+		return &httpProbe{}
+	case "TCP":
+		return &tcpProbe{}
+	case "GRPC":
+		return &grpcProbe{}
 	case "NONE":
 		return nil
 	default:
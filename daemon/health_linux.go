@@ -0,0 +1,67 @@
+package daemon // import "github.com/ellcrys/docker/daemon"
+
+import (
+	"context"
+	"net"
+	"runtime"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vishvananda/netns"
+)
+
+// dialInNetNS connects to the address on the named network from within the
+// network namespace at nsPath. If nsPath is empty, the daemon's own network
+// namespace is used. Host names are resolved by the daemon.
+func dialInNetNS(ctx context.Context, nsPath, network, address string) (net.Conn, error) {
+	var dialer net.Dialer
+	if nsPath == "" {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	type dialResult struct {
+		conn net.Conn
+		err  error
+	}
+	results := make(chan dialResult, 1)
+	go func() {
+		// The socket is created in the namespace of the thread calling
+		// socket(2), and stays there once the thread switches back.
+		runtime.LockOSThread()
+
+		origNS, err := netns.Get()
+		if err != nil {
+			runtime.UnlockOSThread()
+			results <- dialResult{err: err}
+			return
+		}
+		defer origNS.Close()
+
+		targetNS, err := netns.GetFromPath(nsPath)
+		if err != nil {
+			runtime.UnlockOSThread()
+			results <- dialResult{err: err}
+			return
+		}
+		defer targetNS.Close()
+
+		if err := netns.Set(targetNS); err != nil {
+			runtime.UnlockOSThread()
+			results <- dialResult{err: err}
+			return
+		}
+
+		conn, err := dialer.DialContext(ctx, network, address)
+
+		if err := netns.Set(origNS); err != nil {
+			// Keep the thread locked so that it is terminated along with
+			// this goroutine instead of being reused in the wrong namespace.
+			logrus.Errorf("failed to restore network namespace after healthcheck dial: %v", err)
+		} else {
+			runtime.UnlockOSThread()
+		}
+		results <- dialResult{conn: conn, err: err}
+	}()
+
+	r := <-results
+	return r.conn, r.err
+}
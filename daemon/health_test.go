@@ -1,6 +1,10 @@
 package daemon // import "github.com/ellcrys/docker/daemon"
 
 import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -152,3 +156,74 @@ func TestHealthStates(t *testing.T) {
 		t.Errorf("Expecting FailingStreak=0, but got %d\n", c.State.Health.FailingStreak)
 	}
 }
+
+func TestValidateHealthcheckTest(t *testing.T) {
+	for _, tc := range []struct {
+		test  []string
+		valid bool
+	}{
+		{test: nil, valid: true},
+		{test: []string{"CMD", "true"}, valid: true},
+		{test: []string{"HTTP", "http://localhost:8080/healthz"}, valid: true},
+		{test: []string{"HTTP", "https://127.0.0.1/"}, valid: true},
+		{test: []string{"HTTP"}, valid: false},
+		{test: []string{"HTTP", "ftp://localhost/"}, valid: false},
+		{test: []string{"HTTP", "/healthz"}, valid: false},
+		{test: []string{"TCP", "localhost:5432"}, valid: true},
+		{test: []string{"TCP", "localhost"}, valid: false},
+		{test: []string{"TCP", "localhost:5432", "extra"}, valid: false},
+		{test: []string{"GRPC", "localhost:50051"}, valid: true},
+		{test: []string{"GRPC", "localhost:50051", "my.Service"}, valid: true},
+		{test: []string{"GRPC"}, valid: false},
+	} {
+		err := validateHealthcheckTest(tc.test)
+		if tc.valid && err != nil {
+			t.Errorf("Expected %v to be valid, got %v", tc.test, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("Expected %v to be invalid", tc.test)
+		}
+	}
+}
+
+func TestNetworkProbes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddr := closed.Addr().String()
+	closed.Close()
+
+	for _, tc := range []struct {
+		test     []string
+		exitCode int
+	}{
+		{test: []string{"HTTP", srv.URL + "/healthz"}, exitCode: exitStatusHealthy},
+		{test: []string{"HTTP", srv.URL + "/broken"}, exitCode: exitStatusUnhealthy},
+		{test: []string{"HTTP", "http://" + closedAddr + "/healthz"}, exitCode: exitStatusUnhealthy},
+		{test: []string{"TCP", srv.Listener.Addr().String()}, exitCode: exitStatusHealthy},
+		{test: []string{"TCP", closedAddr}, exitCode: exitStatusUnhealthy},
+	} {
+		c := &container.Container{
+			ID: "container_id",
+			Config: &containertypes.Config{
+				Healthcheck: &containertypes.HealthConfig{Test: tc.test},
+			},
+			State: container.NewState(),
+		}
+		result, err := getProbe(c).run(context.Background(), &Daemon{}, c)
+		if err != nil {
+			t.Fatalf("Probe %v failed: %v", tc.test, err)
+		}
+		if result.ExitCode != tc.exitCode {
+			t.Errorf("Expected exit code %d for %v, got %d (%s)", tc.exitCode, tc.test, result.ExitCode, result.Output)
+		}
+	}
+}
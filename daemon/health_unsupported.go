@@ -0,0 +1,20 @@
+// +build !linux
+
+package daemon // import "github.com/ellcrys/docker/daemon"
+
+import (
+	"context"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// dialInNetNS is only supported on Linux, unless no network namespace is
+// given.
+func dialInNetNS(ctx context.Context, nsPath, network, address string) (net.Conn, error) {
+	if nsPath != "" {
+		return nil, errors.New("network healthchecks are not supported on this platform")
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, network, address)
+}
@@ -143,11 +143,15 @@ func (i *ImageService) pullForBuilder(ctx context.Context, name string, authConf
 		return nil, err
 	}
 	ref = reference.TagNameOnly(ref)
+	remoteRef, err := i.rewriteReference(ref)
+	if err != nil {
+		return nil, err
+	}
 
 	pullRegistryAuth := &types.AuthConfig{}
 	if len(authConfigs) > 0 {
 		// The request came with a full auth config, use it
-		repoInfo, err := i.registryService.ResolveRepository(ref)
+		repoInfo, err := i.registryService.ResolveRepository(remoteRef)
 		if err != nil {
 			return nil, err
 		}
@@ -156,7 +160,7 @@ func (i *ImageService) pullForBuilder(ctx context.Context, name string, authConf
 		pullRegistryAuth = &resolvedConfig
 	}
 
-	if err := i.pullImageWithReference(ctx, ref, os, nil, pullRegistryAuth, output); err != nil {
+	if err := i.pullImageWithReference(ctx, ref, remoteRef, os, nil, pullRegistryAuth, output); err != nil {
 		return nil, err
 	}
 	return i.GetImage(name)
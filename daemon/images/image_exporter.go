@@ -9,11 +9,13 @@ import (
 // ExportImage exports a list of images to the given output stream. The
 // exported images are archived into a tar when written to the output
 // stream. All images with the given tag and all versions containing
-// the same tag are exported. names is the set of tags to export, and
-// outStream is the writer which the images are written to.
-func (i *ImageService) ExportImage(names []string, outStream io.Writer) error {
+// the same tag are exported. names is the set of tags to export, format
+// is the format of the tar, either tarexport.FormatDocker (the default) or
+// tarexport.FormatOCI, and outStream is the writer which the images are
+// written to.
+func (i *ImageService) ExportImage(names []string, format string, outStream io.Writer) error {
 	imageExporter := tarexport.NewTarExporter(i.imageStore, i.layerStores, i.referenceStore, i)
-	return imageExporter.Save(names, outStream)
+	return imageExporter.Save(names, format, outStream)
 }
 
 // LoadImage uploads a set of images into the repository. This is the
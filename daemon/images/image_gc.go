@@ -0,0 +1,266 @@
+package images // import "github.com/ellcrys/docker/daemon/images"
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	"github.com/ellcrys/docker/api/types"
+	"github.com/ellcrys/docker/image"
+	"github.com/ellcrys/docker/layer"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ImageGCPolicy configures the automatic removal of unused images once the
+// filesystem holding them fills up.
+type ImageGCPolicy struct {
+	// Root is a directory on the filesystem whose usage is watched.
+	Root string
+	// HighWatermark is the disk usage, in percent, over which unused images
+	// are removed.
+	HighWatermark int
+	// LowWatermark is the disk usage, in percent, under which the removal
+	// of images stops.
+	LowWatermark int
+	// Interval is the time between two checks of the disk usage.
+	Interval time.Duration
+	// Protect holds the rules matching the images which are never removed,
+	// as "label=<key>[=<value>]" or "reference=<pattern>".
+	Protect []string
+}
+
+// imageGCProtection holds the parsed protection rules of an ImageGCPolicy.
+type imageGCProtection struct {
+	labels     []string
+	references []string
+}
+
+func parseImageGCProtection(rules []string) (*imageGCProtection, error) {
+	p := &imageGCProtection{}
+	for _, rule := range rules {
+		kv := strings.SplitN(rule, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return nil, fmt.Errorf("invalid image gc protection rule: %s", rule)
+		}
+		switch kv[0] {
+		case "label":
+			p.labels = append(p.labels, kv[1])
+		case "reference":
+			if _, err := path.Match(kv[1], ""); err != nil {
+				return nil, errors.Wrapf(err, "invalid image gc protection rule: %s", rule)
+			}
+			p.references = append(p.references, kv[1])
+		default:
+			return nil, fmt.Errorf("invalid image gc protection rule: %s", rule)
+		}
+	}
+	return p, nil
+}
+
+// protects returns whether the image, known by refs, is matched by one of the
+// protection rules.
+func (p *imageGCProtection) protects(img *image.Image, refs []reference.Named) bool {
+	if img.Config != nil {
+		for _, label := range p.labels {
+			kv := strings.SplitN(label, "=", 2)
+			value, ok := img.Config.Labels[kv[0]]
+			if ok && (len(kv) == 1 || kv[1] == value) {
+				return true
+			}
+		}
+	}
+	for _, pattern := range p.references {
+		for _, ref := range refs {
+			if found, _ := reference.FamiliarMatch(pattern, ref); found {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type imageGCCandidate struct {
+	id       image.ID
+	lastUsed time.Time
+}
+
+// StartImageGC starts removing unused images in the background, following
+// the policy. Images are removed least recently used first. The collector is
+// stopped by Cleanup.
+func (i *ImageService) StartImageGC(policy ImageGCPolicy) error {
+	protection, err := parseImageGCProtection(policy.Protect)
+	if err != nil {
+		return err
+	}
+	if _, err := diskUsage(policy.Root); err != nil {
+		return errors.Wrap(err, "image gc is not supported")
+	}
+
+	i.gcStop = make(chan struct{})
+	go func(stop chan struct{}) {
+		ticker := time.NewTicker(policy.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := i.collectImages(policy, protection, stop); err != nil {
+					logrus.WithError(err).Warn("image gc failed")
+				}
+			}
+		}
+	}(i.gcStop)
+	return nil
+}
+
+// collectImages removes unused images while the disk usage is over the
+// policy's low watermark, if it went over its high watermark.
+func (i *ImageService) collectImages(policy ImageGCPolicy, protection *imageGCProtection, stop chan struct{}) error {
+	usage, err := diskUsage(policy.Root)
+	if err != nil {
+		return err
+	}
+	if usage < policy.HighWatermark {
+		return nil
+	}
+
+	// the image gc and image prune delete the same images
+	if !atomic.CompareAndSwapInt32(&i.pruneRunning, 0, 1) {
+		return nil
+	}
+	defer atomic.StoreInt32(&i.pruneRunning, 0)
+
+	logrus.Infof("image gc: disk usage of %s is %d%%, removing unused images", policy.Root, usage)
+	allLayers := i.allLayers()
+	var removed int
+	var reclaimed uint64
+	for _, c := range i.imageGCCandidates(protection) {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+		if usage <= policy.LowWatermark {
+			break
+		}
+
+		deleted := i.deleteImageForGC(c.id)
+		if len(deleted) == 0 {
+			continue
+		}
+		size := spaceReclaimed(allLayers, deleted)
+		removed++
+		reclaimed += size
+		i.LogImageEventWithAttributes(c.id.String(), "", "gc", map[string]string{
+			"reclaimed": strconv.FormatUint(size, 10),
+		})
+
+		if usage, err = diskUsage(policy.Root); err != nil {
+			return err
+		}
+	}
+	logrus.Infof("image gc: removed %d images, reclaimed %d bytes, disk usage of %s is %d%%", removed, reclaimed, policy.Root, usage)
+	return nil
+}
+
+// imageGCCandidates returns the images which the image gc may remove, least
+// recently used first. Images used by containers, images with children,
+// which would not free any space, and protected images are left out.
+func (i *ImageService) imageGCCandidates(protection *imageGCProtection) []imageGCCandidate {
+	used := make(map[image.ID]bool)
+	for _, c := range i.containers.List() {
+		used[c.ImageID] = true
+	}
+
+	var candidates []imageGCCandidate
+	for id, img := range i.imageStore.Map() {
+		if used[id] || len(i.imageStore.Children(id)) != 0 {
+			continue
+		}
+		if protection.protects(img, i.referenceStore.References(id.Digest())) {
+			continue
+		}
+		candidates = append(candidates, imageGCCandidate{id: id, lastUsed: i.imageLastUsed(id, img)})
+	}
+	sortImageGCCandidates(candidates)
+	return candidates
+}
+
+func sortImageGCCandidates(candidates []imageGCCandidate) {
+	sort.Slice(candidates, func(a, b int) bool {
+		return candidates[a].lastUsed.Before(candidates[b].lastUsed)
+	})
+}
+
+// imageLastUsed returns the last time the image was used to create a
+// container, or was pulled, built or tagged. The image creation time is used
+// for images which were neither.
+func (i *ImageService) imageLastUsed(id image.ID, img *image.Image) time.Time {
+	lastUsed, _ := i.imageStore.GetLastUsed(id)
+	if lastUpdated, _ := i.imageStore.GetLastUpdated(id); lastUpdated.After(lastUsed) {
+		lastUsed = lastUpdated
+	}
+	if lastUsed.IsZero() {
+		return img.Created
+	}
+	return lastUsed
+}
+
+// deleteImageForGC removes all the references to the image, and the image.
+func (i *ImageService) deleteImageForGC(id image.ID) []types.ImageDeleteResponseItem {
+	var deleted []types.ImageDeleteResponseItem
+	refs := i.referenceStore.References(id.Digest())
+	if len(refs) == 0 {
+		hex := id.Digest().Hex()
+		imgDel, err := i.ImageDelete(hex, false, true)
+		if imageDeleteFailed(hex, err) {
+			return nil
+		}
+		return imgDel
+	}
+	for _, ref := range refs {
+		imgDel, err := i.ImageDelete(ref.String(), false, true)
+		if imageDeleteFailed(ref.String(), err) {
+			continue
+		}
+		deleted = append(deleted, imgDel...)
+	}
+	return deleted
+}
+
+// allLayers returns the layers of all the layer stores.
+func (i *ImageService) allLayers() map[layer.ChainID]layer.Layer {
+	allLayers := make(map[layer.ChainID]layer.Layer)
+	for _, ls := range i.layerStores {
+		for k, v := range ls.Map() {
+			allLayers[k] = v
+		}
+	}
+	return allLayers
+}
+
+// spaceReclaimed returns the size of the layers removed along with images.
+func spaceReclaimed(allLayers map[layer.ChainID]layer.Layer, deleted []types.ImageDeleteResponseItem) uint64 {
+	var reclaimed uint64
+	for _, d := range deleted {
+		if d.Deleted != "" {
+			chid := layer.ChainID(d.Deleted)
+			if l, ok := allLayers[chid]; ok {
+				diffSize, err := l.DiffSize()
+				if err != nil {
+					logrus.Warnf("failed to get layer %s size: %v", chid, err)
+					continue
+				}
+				reclaimed += uint64(diffSize)
+			}
+		}
+	}
+	return reclaimed
+}
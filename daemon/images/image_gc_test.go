@@ -0,0 +1,60 @@
+package images // import "github.com/ellcrys/docker/daemon/images"
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	containertypes "github.com/ellcrys/docker/api/types/container"
+	"github.com/ellcrys/docker/image"
+	"github.com/gotestyourself/gotestyourself/assert"
+	is "github.com/gotestyourself/gotestyourself/assert/cmp"
+)
+
+func TestParseImageGCProtection(t *testing.T) {
+	_, err := parseImageGCProtection([]string{"label=keep", "reference=alpine:*"})
+	assert.Check(t, err)
+
+	for _, rule := range []string{"label", "label=", "name=foo", "reference=[a"} {
+		_, err := parseImageGCProtection([]string{rule})
+		assert.Check(t, is.ErrorContains(err, "invalid image gc protection rule"), rule)
+	}
+}
+
+func TestImageGCProtects(t *testing.T) {
+	p, err := parseImageGCProtection([]string{"label=keep", "label=tier=base", "reference=alpine:*"})
+	assert.NilError(t, err)
+
+	withLabels := func(labels map[string]string) *image.Image {
+		img := &image.Image{}
+		img.Config = &containertypes.Config{Labels: labels}
+		return img
+	}
+	named := func(s string) []reference.Named {
+		ref, err := reference.ParseNormalizedNamed(s)
+		assert.NilError(t, err)
+		return []reference.Named{ref}
+	}
+
+	assert.Check(t, p.protects(withLabels(map[string]string{"keep": ""}), nil))
+	assert.Check(t, p.protects(withLabels(map[string]string{"tier": "base"}), nil))
+	assert.Check(t, !p.protects(withLabels(map[string]string{"tier": "app"}), nil))
+	assert.Check(t, p.protects(&image.Image{}, named("alpine:3.7")))
+	assert.Check(t, !p.protects(&image.Image{}, named("busybox:latest")))
+}
+
+func TestSortImageGCCandidates(t *testing.T) {
+	now := time.Now()
+	candidates := []imageGCCandidate{
+		{id: "recent", lastUsed: now},
+		{id: "oldest", lastUsed: now.Add(-2 * time.Hour)},
+		{id: "old", lastUsed: now.Add(-time.Hour)},
+	}
+	sortImageGCCandidates(candidates)
+
+	var ids []image.ID
+	for _, c := range candidates {
+		ids = append(ids, c.id)
+	}
+	assert.Check(t, is.DeepEqual([]image.ID{"oldest", "old", "recent"}, ids))
+}
@@ -0,0 +1,22 @@
+// +build linux freebsd
+
+package images // import "github.com/ellcrys/docker/daemon/images"
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// diskUsage returns the usage, in percent, of the filesystem holding path,
+// counting the blocks reserved to root as free like df(1).
+func diskUsage(path string) (int, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	used := uint64(st.Blocks) - uint64(st.Bfree)
+	total := used + uint64(st.Bavail)
+	if total == 0 {
+		return 0, nil
+	}
+	return int(used * 100 / total), nil
+}
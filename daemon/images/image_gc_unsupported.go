@@ -0,0 +1,11 @@
+// +build !linux,!freebsd
+
+package images // import "github.com/ellcrys/docker/daemon/images"
+
+import (
+	"errors"
+)
+
+func diskUsage(path string) (int, error) {
+	return 0, errors.New("disk usage is not supported on this platform")
+}
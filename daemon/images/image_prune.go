@@ -12,16 +12,16 @@ import (
 	timetypes "github.com/ellcrys/docker/api/types/time"
 	"github.com/ellcrys/docker/errdefs"
 	"github.com/ellcrys/docker/image"
-	"github.com/ellcrys/docker/layer"
 	"github.com/opencontainers/go-digest"
 	"github.com/sirupsen/logrus"
 )
 
 var imagesAcceptedFilters = map[string]bool{
-	"dangling": true,
-	"label":    true,
-	"label!":   true,
-	"until":    true,
+	"dangling":         true,
+	"label":            true,
+	"label!":           true,
+	"last-used-before": true,
+	"until":            true,
 }
 
 // errPruneRunning is returned when a prune request is received while
@@ -56,6 +56,10 @@ func (i *ImageService) ImagesPrune(ctx context.Context, pruneFilters filters.Arg
 	if err != nil {
 		return nil, err
 	}
+	lastUsedBefore, err := getTimestampFilter(pruneFilters, "last-used-before")
+	if err != nil {
+		return nil, err
+	}
 
 	var allImages map[image.ID]*image.Image
 	if danglingOnly {
@@ -65,12 +69,7 @@ func (i *ImageService) ImagesPrune(ctx context.Context, pruneFilters filters.Arg
 	}
 
 	// Filter intermediary images and get their unique size
-	allLayers := make(map[layer.ChainID]layer.Layer)
-	for _, ls := range i.layerStores {
-		for k, v := range ls.Map() {
-			allLayers[k] = v
-		}
-	}
+	allLayers := i.allLayers()
 	topImages := map[image.ID]*image.Image{}
 	for id, img := range allImages {
 		select {
@@ -84,6 +83,9 @@ func (i *ImageService) ImagesPrune(ctx context.Context, pruneFilters filters.Arg
 			if !until.IsZero() && img.Created.After(until) {
 				continue
 			}
+			if !lastUsedBefore.IsZero() && !i.imageLastUsed(id, img).Before(lastUsedBefore) {
+				continue
+			}
 			if img.Config != nil && !matchLabels(pruneFilters, img.Config.Labels) {
 				continue
 			}
@@ -141,19 +143,7 @@ deleteImagesLoop:
 	}
 
 	// Compute how much space was freed
-	for _, d := range rep.ImagesDeleted {
-		if d.Deleted != "" {
-			chid := layer.ChainID(d.Deleted)
-			if l, ok := allLayers[chid]; ok {
-				diffSize, err := l.DiffSize()
-				if err != nil {
-					logrus.Warnf("failed to get layer %s size: %v", chid, err)
-					continue
-				}
-				rep.SpaceReclaimed += uint64(diffSize)
-			}
-		}
-	}
+	rep.SpaceReclaimed = spaceReclaimed(allLayers, rep.ImagesDeleted)
 
 	if canceled {
 		logrus.Debugf("ImagesPrune operation cancelled: %#v", *rep)
@@ -189,22 +179,29 @@ func matchLabels(pruneFilters filters.Args, labels map[string]string) bool {
 }
 
 func getUntilFromPruneFilters(pruneFilters filters.Args) (time.Time, error) {
-	until := time.Time{}
-	if !pruneFilters.Contains("until") {
-		return until, nil
+	return getTimestampFilter(pruneFilters, "until")
+}
+
+// getTimestampFilter returns the time set by the filter named name, either as
+// a timestamp or as a duration relative to now, or the zero time if the filter
+// is not set.
+func getTimestampFilter(imageFilters filters.Args, name string) (time.Time, error) {
+	t := time.Time{}
+	if !imageFilters.Contains(name) {
+		return t, nil
 	}
-	untilFilters := pruneFilters.Get("until")
-	if len(untilFilters) > 1 {
-		return until, fmt.Errorf("more than one until filter specified")
+	values := imageFilters.Get(name)
+	if len(values) > 1 {
+		return t, fmt.Errorf("more than one %s filter specified", name)
 	}
-	ts, err := timetypes.GetTimestamp(untilFilters[0], time.Now())
+	ts, err := timetypes.GetTimestamp(values[0], time.Now())
 	if err != nil {
-		return until, err
+		return t, err
 	}
 	seconds, nanoseconds, err := timetypes.ParseTimestamps(ts, 0)
 	if err != nil {
-		return until, err
+		return t, err
 	}
-	until = time.Unix(seconds, nanoseconds)
-	return until, nil
+	t = time.Unix(seconds, nanoseconds)
+	return t, nil
 }
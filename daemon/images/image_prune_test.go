@@ -0,0 +1,31 @@
+package images // import "github.com/ellcrys/docker/daemon/images"
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ellcrys/docker/api/types/filters"
+	"github.com/gotestyourself/gotestyourself/assert"
+	is "github.com/gotestyourself/gotestyourself/assert/cmp"
+)
+
+func TestGetTimestampFilter(t *testing.T) {
+	ts, err := getTimestampFilter(filters.NewArgs(), "last-used-before")
+	assert.NilError(t, err)
+	assert.Check(t, ts.IsZero())
+
+	ts, err = getTimestampFilter(filters.NewArgs(filters.Arg("last-used-before", "1514764800")), "last-used-before")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(ts.Unix(), int64(1514764800)))
+
+	ts, err = getTimestampFilter(filters.NewArgs(filters.Arg("last-used-before", "1h")), "last-used-before")
+	assert.NilError(t, err)
+	assert.Check(t, ts.Before(time.Now().Add(-59*time.Minute)))
+
+	args := filters.NewArgs(filters.Arg("last-used-before", "1h"), filters.Arg("last-used-before", "2h"))
+	_, err = getTimestampFilter(args, "last-used-before")
+	assert.Check(t, is.ErrorContains(err, "more than one last-used-before filter specified"))
+
+	_, err = getTimestampFilter(filters.NewArgs(filters.Arg("last-used-before", "yesterday")), "last-used-before")
+	assert.Check(t, is.ErrorContains(err, ""))
+}
@@ -13,6 +13,7 @@ import (
 	progressutils "github.com/ellcrys/docker/distribution/utils"
 	"github.com/ellcrys/docker/errdefs"
 	"github.com/ellcrys/docker/pkg/progress"
+	refstore "github.com/ellcrys/docker/reference"
 	"github.com/ellcrys/docker/registry"
 	"github.com/opencontainers/go-digest"
 )
@@ -44,10 +45,22 @@ func (i *ImageService) PullImage(ctx context.Context, image, tag, os string, met
 		}
 	}
 
-	return i.pullImageWithReference(ctx, ref, os, metaHeaders, authConfig, outStream)
+	remoteRef, err := i.rewriteReference(ref)
+	if err != nil {
+		return err
+	}
+	if reference.Domain(remoteRef) != reference.Domain(ref) {
+		// the credentials sent by the client are for the registry of the
+		// reference, they must not be sent to another registry
+		authConfig = &types.AuthConfig{}
+	}
+
+	return i.pullImageWithReference(ctx, ref, remoteRef, os, metaHeaders, authConfig, outStream)
 }
 
-func (i *ImageService) pullImageWithReference(ctx context.Context, ref reference.Named, os string, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
+// pullImageWithReference pulls the image from remoteRef, the reference
+// rewritten from ref by the registry rewrites, and tags it as ref.
+func (i *ImageService) pullImageWithReference(ctx context.Context, ref, remoteRef reference.Named, os string, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
 	// Include a buffer so that slow client connections don't affect
 	// transfer performance.
 	progressChan := make(chan progress.Progress, 100)
@@ -66,6 +79,12 @@ func (i *ImageService) pullImageWithReference(ctx context.Context, ref reference
 		os = runtime.GOOS
 	}
 
+	var referenceStore refstore.Store = i.referenceStore
+	if remoteRef.Name() != ref.Name() {
+		progress.Messagef(progress.ChanOutput(progressChan), "", "Pulling %s from %s", reference.FamiliarName(ref), reference.FamiliarName(remoteRef))
+		referenceStore = &rewrittenReferenceStore{Store: i.referenceStore, remote: remoteRef, local: ref}
+	}
+
 	imagePullConfig := &distribution.ImagePullConfig{
 		Config: distribution.Config{
 			MetaHeaders:      metaHeaders,
@@ -75,14 +94,19 @@ func (i *ImageService) pullImageWithReference(ctx context.Context, ref reference
 			ImageEventLogger: i.LogImageEvent,
 			MetadataStore:    i.distributionMetadataStore,
 			ImageStore:       distribution.NewImageConfigStoreFromStore(i.imageStore),
-			ReferenceStore:   i.referenceStore,
+			ReferenceStore:   referenceStore,
 		},
-		DownloadManager: i.downloadManager,
-		Schema2Types:    distribution.ImageTypes,
-		OS:              os,
+		DownloadManager:   i.downloadManager,
+		Schema2Types:      distribution.ImageTypes,
+		OS:                os,
+		PartialDownloads:  i.partialDownloads,
+		MaxDownloadChunks: i.maxDownloadChunks,
+	}
+	if i.signaturePolicy != nil {
+		imagePullConfig.SignatureVerifier = i.signaturePolicy
 	}
 
-	err := distribution.Pull(ctx, ref, imagePullConfig)
+	err := distribution.Pull(ctx, remoteRef, imagePullConfig)
 	close(progressChan)
 	<-writesDone
 	return err
@@ -0,0 +1,62 @@
+package images // import "github.com/ellcrys/docker/daemon/images"
+
+import (
+	"github.com/docker/distribution/reference"
+	"github.com/ellcrys/docker/image"
+	"github.com/opencontainers/go-digest"
+)
+
+// VerifyImageSignature returns an error if the signature policy of the daemon
+// denies running img, as referred to by refOrID. If refOrID is a reference of
+// the image, the policy of its repository applies. Otherwise the image is
+// allowed if the policy allows it from any of its repositories. The images
+// not in any repository, such as the images built locally, are verified as
+// their closest parent in a repository.
+func (i *ImageService) VerifyImageSignature(refOrID string, img *image.Image) error {
+	if i.signaturePolicy == nil {
+		return nil
+	}
+
+	var named reference.Named
+	if ref, err := reference.ParseNormalizedNamed(refOrID); err == nil {
+		if id, err := i.referenceStore.Get(reference.TagNameOnly(ref)); err == nil && id == img.ID().Digest() {
+			named = reference.TrimNamed(ref)
+		}
+	}
+
+	id := img.ID()
+	for {
+		var (
+			names   []reference.Named
+			digests = make(map[string][]digest.Digest)
+		)
+		for _, ref := range i.referenceStore.References(id.Digest()) {
+			if named != nil && ref.Name() != named.Name() {
+				continue
+			}
+			if _, ok := digests[ref.Name()]; !ok {
+				names = append(names, reference.TrimNamed(ref))
+				digests[ref.Name()] = nil
+			}
+			if canonical, ok := ref.(reference.Canonical); ok {
+				digests[ref.Name()] = append(digests[ref.Name()], canonical.Digest())
+			}
+		}
+
+		if len(names) > 0 {
+			var err error
+			for _, name := range names {
+				if err = i.signaturePolicy.VerifyDigests(name, digests[name.Name()]); err == nil {
+					return nil
+				}
+			}
+			return err
+		}
+
+		parent, err := i.imageStore.GetParent(id)
+		if err != nil || parent == "" {
+			return i.signaturePolicy.VerifyDigests(nil, nil)
+		}
+		id = parent
+	}
+}
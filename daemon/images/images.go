@@ -18,11 +18,12 @@ import (
 )
 
 var acceptedImageFilterTags = map[string]bool{
-	"dangling":  true,
-	"label":     true,
-	"before":    true,
-	"since":     true,
-	"reference": true,
+	"dangling":         true,
+	"label":            true,
+	"before":           true,
+	"since":            true,
+	"reference":        true,
+	"last-used-before": true,
 }
 
 // byCreated is a temporary type used to sort a list of images by creation
@@ -84,6 +85,11 @@ func (i *ImageService) Images(imageFilters filters.Args, all bool, withExtraAttr
 		return nil, err
 	}
 
+	lastUsedBefore, err := getTimestampFilter(imageFilters, "last-used-before")
+	if err != nil {
+		return nil, err
+	}
+
 	images := []*types.ImageSummary{}
 	var imagesMap map[*image.Image]*types.ImageSummary
 	var layerRefs map[layer.ChainID]int
@@ -103,6 +109,10 @@ func (i *ImageService) Images(imageFilters filters.Args, all bool, withExtraAttr
 			}
 		}
 
+		if !lastUsedBefore.IsZero() && !i.imageLastUsed(id, img).Before(lastUsedBefore) {
+			continue
+		}
+
 		if imageFilters.Contains("label") {
 			// Very old image that do not have image.Config (or even labels)
 			if img.Config == nil {
@@ -142,6 +152,9 @@ func (i *ImageService) Images(imageFilters filters.Args, all bool, withExtraAttr
 		}
 
 		newImage := newImage(img, size)
+		if lastUsed, err := i.imageStore.GetLastUsed(id); err == nil && !lastUsed.IsZero() {
+			newImage.LastUsed = lastUsed.Unix()
+		}
 
 		for _, ref := range i.referenceStore.References(id.Digest()) {
 			if imageFilters.Contains("reference") {
@@ -0,0 +1,81 @@
+package images // import "github.com/ellcrys/docker/daemon/images"
+
+import (
+	"github.com/docker/distribution/reference"
+	"github.com/ellcrys/docker/errdefs"
+	refstore "github.com/ellcrys/docker/reference"
+	"github.com/opencontainers/go-digest"
+)
+
+// rewriteReference returns the reference to pull the image from, as
+// rewritten by the registry rewrites configured.
+func (i *ImageService) rewriteReference(ref reference.Named) (reference.Named, error) {
+	remoteRef, _, err := i.registryService.RewriteReference(ref)
+	if err != nil {
+		return nil, errdefs.InvalidParameter(err)
+	}
+	return remoteRef, nil
+}
+
+// rewrittenReferenceStore is the reference store of an image pulled from a
+// rewritten reference. The references of the remote repository are stored as
+// the references of the local repository.
+type rewrittenReferenceStore struct {
+	refstore.Store
+	remote reference.Named
+	local  reference.Named
+}
+
+func (s *rewrittenReferenceStore) localReference(ref reference.Named) (reference.Named, error) {
+	if ref.Name() != s.remote.Name() {
+		return ref, nil
+	}
+	local := reference.TrimNamed(s.local)
+	if canonical, ok := ref.(reference.Canonical); ok {
+		return reference.WithDigest(local, canonical.Digest())
+	}
+	if tagged, ok := ref.(reference.Tagged); ok {
+		return reference.WithTag(local, tagged.Tag())
+	}
+	return local, nil
+}
+
+func (s *rewrittenReferenceStore) ReferencesByName(ref reference.Named) []refstore.Association {
+	local, err := s.localReference(ref)
+	if err != nil {
+		return nil
+	}
+	return s.Store.ReferencesByName(local)
+}
+
+func (s *rewrittenReferenceStore) AddTag(ref reference.Named, id digest.Digest, force bool) error {
+	local, err := s.localReference(ref)
+	if err != nil {
+		return err
+	}
+	return s.Store.AddTag(local, id, force)
+}
+
+func (s *rewrittenReferenceStore) AddDigest(ref reference.Canonical, id digest.Digest, force bool) error {
+	local, err := s.localReference(ref)
+	if err != nil {
+		return err
+	}
+	return s.Store.AddDigest(local.(reference.Canonical), id, force)
+}
+
+func (s *rewrittenReferenceStore) Delete(ref reference.Named) (bool, error) {
+	local, err := s.localReference(ref)
+	if err != nil {
+		return false, err
+	}
+	return s.Store.Delete(local)
+}
+
+func (s *rewrittenReferenceStore) Get(ref reference.Named) (digest.Digest, error) {
+	local, err := s.localReference(ref)
+	if err != nil {
+		return "", err
+	}
+	return s.Store.Get(local)
+}
@@ -0,0 +1,47 @@
+package images // import "github.com/ellcrys/docker/daemon/images"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/distribution/reference"
+	refstore "github.com/ellcrys/docker/reference"
+	"github.com/gotestyourself/gotestyourself/assert"
+	is "github.com/gotestyourself/gotestyourself/assert/cmp"
+	"github.com/opencontainers/go-digest"
+)
+
+func TestRewrittenReferenceStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rewritten-reference-store")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := refstore.NewReferenceStore(filepath.Join(dir, "repositories.json"))
+	assert.NilError(t, err)
+
+	local, err := reference.ParseNormalizedNamed("alpine:3.7")
+	assert.NilError(t, err)
+	remote, err := reference.ParseNormalizedNamed("registry.corp/hub/alpine:3.7")
+	assert.NilError(t, err)
+	rs := &rewrittenReferenceStore{Store: store, remote: remote, local: local}
+
+	id := digest.FromString("image")
+	assert.NilError(t, rs.AddTag(remote, id, true))
+	manifest := digest.FromString("manifest")
+	remoteDigest, err := reference.WithDigest(reference.TrimNamed(remote), manifest)
+	assert.NilError(t, err)
+	assert.NilError(t, rs.AddDigest(remoteDigest, id, true))
+
+	refs := store.References(id)
+	var names []string
+	for _, ref := range refs {
+		names = append(names, reference.FamiliarString(ref))
+	}
+	assert.Check(t, is.DeepEqual(names, []string{"alpine:3.7", "alpine@" + manifest.String()}))
+
+	got, err := rs.Get(remote)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(got, id))
+}
@@ -9,7 +9,9 @@ import (
 	"github.com/ellcrys/docker/distribution/metadata"
 	"github.com/ellcrys/docker/distribution/xfer"
 	"github.com/ellcrys/docker/image"
+	"github.com/ellcrys/docker/image/signature"
 	"github.com/ellcrys/docker/layer"
+	"github.com/ellcrys/docker/pkg/archive"
 	dockerreference "github.com/ellcrys/docker/reference"
 	"github.com/ellcrys/docker/registry"
 	"github.com/docker/libtrust"
@@ -33,11 +35,15 @@ type ImageServiceConfig struct {
 	DistributionMetadataStore metadata.Store
 	EventsService             *daemonevents.Events
 	ImageStore                image.Store
+	LayerCompression          archive.Compression
 	LayerStores               map[string]layer.Store
 	MaxConcurrentDownloads    int
 	MaxConcurrentUploads      int
+	MaxDownloadChunks         int
+	PartialDownloads          *xfer.PartialDownloads
 	ReferenceStore            dockerreference.Store
 	RegistryService           registry.Service
+	SignaturePolicy           *signature.Policy
 	TrustKey                  libtrust.PrivateKey
 }
 
@@ -51,9 +57,13 @@ func NewImageService(config ImageServiceConfig) *ImageService {
 		downloadManager:           xfer.NewLayerDownloadManager(config.LayerStores, config.MaxConcurrentDownloads),
 		eventsService:             config.EventsService,
 		imageStore:                config.ImageStore,
+		layerCompression:          config.LayerCompression,
 		layerStores:               config.LayerStores,
+		maxDownloadChunks:         config.MaxDownloadChunks,
+		partialDownloads:          config.PartialDownloads,
 		referenceStore:            config.ReferenceStore,
 		registryService:           config.RegistryService,
+		signaturePolicy:           config.SignaturePolicy,
 		trustKey:                  config.TrustKey,
 		uploadManager:             xfer.NewLayerUploadManager(config.MaxConcurrentUploads),
 	}
@@ -65,11 +75,16 @@ type ImageService struct {
 	distributionMetadataStore metadata.Store
 	downloadManager           *xfer.LayerDownloadManager
 	eventsService             *daemonevents.Events
+	gcStop                    chan struct{}
 	imageStore                image.Store
+	layerCompression          archive.Compression
 	layerStores               map[string]layer.Store // By operating system
+	maxDownloadChunks         int
+	partialDownloads          *xfer.PartialDownloads
 	pruneRunning              int32
 	referenceStore            dockerreference.Store
 	registryService           registry.Service
+	signaturePolicy           *signature.Policy
 	trustKey                  libtrust.PrivateKey
 	uploadManager             *xfer.LayerUploadManager
 }
@@ -108,7 +123,17 @@ func (i *ImageService) CreateLayer(container *container.Container, initFunc laye
 
 	// Indexing by OS is safe here as validation of OS has already been performed in create() (the only
 	// caller), and guaranteed non-nil
-	return i.layerStores[container.OS].CreateRWLayer(container.ID, layerID, rwLayerOpts)
+	rwLayer, err := i.layerStores[container.OS].CreateRWLayer(container.ID, layerID, rwLayerOpts)
+	if err != nil {
+		return nil, err
+	}
+	if container.ImageID != "" {
+		// recorded for the image garbage collector
+		if err := i.imageStore.SetLastUsed(container.ImageID); err != nil {
+			logrus.WithError(err).Warnf("failed to record last use of image %s", container.ImageID)
+		}
+	}
+	return rwLayer, nil
 }
 
 // GetLayerByID returns a layer by ID and operating system
@@ -138,6 +163,10 @@ func (i *ImageService) GetLayerMountID(cid string, os string) (string, error) {
 // Cleanup resources before the process is shutdown.
 // called from daemon.go Daemon.Shutdown()
 func (i *ImageService) Cleanup() {
+	if i.gcStop != nil {
+		close(i.gcStop)
+		i.gcStop = nil
+	}
 	for os, ls := range i.layerStores {
 		if ls != nil {
 			if err := ls.Cleanup(); err != nil {
@@ -13,6 +13,7 @@ import (
 	"github.com/ellcrys/docker/container"
 	"github.com/ellcrys/docker/daemon/network"
 	"github.com/ellcrys/docker/errdefs"
+	"github.com/ellcrys/docker/volume"
 	volumestore "github.com/ellcrys/docker/volume/store"
 	"github.com/docker/go-connections/nat"
 )
@@ -249,6 +250,18 @@ func (daemon *Daemon) VolumeInspect(name string) (*types.Volume, error) {
 	apiV := volumeToAPIType(v)
 	apiV.Mountpoint = v.Path()
 	apiV.Status = v.Status()
+	if u, ok := daemon.volumes.GetUsage(v.Name()); ok {
+		apiV.UsageData = volumeUsageToAPIType(u, len(daemon.volumes.Refs(v)))
+	}
+	if uv, ok := v.(volume.UsageVolume); ok {
+		// volumes limited in size report their current usage
+		if size, limit, err := uv.Usage(); err == nil {
+			if apiV.UsageData == nil {
+				apiV.UsageData = &types.VolumeUsageData{RefCount: int64(len(daemon.volumes.Refs(v)))}
+			}
+			apiV.UsageData.Size, apiV.UsageData.Limit = size, limit
+		}
+	}
 	return apiV, nil
 }
 
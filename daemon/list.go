@@ -19,10 +19,11 @@ import (
 )
 
 var acceptedVolumeFilterTags = map[string]bool{
-	"dangling": true,
-	"name":     true,
-	"driver":   true,
-	"label":    true,
+	"dangling":     true,
+	"name":         true,
+	"driver":       true,
+	"label":        true,
+	"unused-since": true,
 }
 
 var acceptedPsFilterTags = map[string]bool{
@@ -607,7 +608,7 @@ func (daemon *Daemon) refreshImage(s *container.Snapshot, ctx *listContext) (*ty
 
 // Volumes lists known volumes, using the filter to restrict the range
 // of volumes returned.
-func (daemon *Daemon) Volumes(filter string) ([]*types.Volume, []string, error) {
+func (daemon *Daemon) Volumes(filter, sortBy string) ([]*types.Volume, []string, error) {
 	var (
 		volumesOut []*types.Volume
 	)
@@ -615,6 +616,9 @@ func (daemon *Daemon) Volumes(filter string) ([]*types.Volume, []string, error)
 	if err != nil {
 		return nil, nil, err
 	}
+	if sortBy != "" && sortBy != "name" && sortBy != "size" {
+		return nil, nil, errdefs.InvalidParameter(errors.Errorf("invalid sort order %q, valid orders are name and size", sortBy))
+	}
 
 	if err := volFilters.Validate(acceptedVolumeFilterTags); err != nil {
 		return nil, nil, err
@@ -638,8 +642,29 @@ func (daemon *Daemon) Volumes(filter string) ([]*types.Volume, []string, error)
 		} else {
 			apiV.Mountpoint = v.Path()
 		}
+		if u, ok := daemon.volumes.GetUsage(v.Name()); ok {
+			apiV.UsageData = volumeUsageToAPIType(u, len(daemon.volumes.Refs(v)))
+		}
 		volumesOut = append(volumesOut, apiV)
 	}
+
+	switch sortBy {
+	case "name":
+		sort.Slice(volumesOut, func(i, j int) bool {
+			return volumesOut[i].Name < volumesOut[j].Name
+		})
+	case "size":
+		// largest first, volumes which usage is unknown last
+		size := func(v *types.Volume) int64 {
+			if v.UsageData == nil {
+				return -1
+			}
+			return v.UsageData.Size
+		}
+		sort.SliceStable(volumesOut, func(i, j int) bool {
+			return size(volumesOut[i]) > size(volumesOut[j])
+		})
+	}
 	return volumesOut, warnings, nil
 }
 
@@ -683,6 +708,13 @@ func (daemon *Daemon) filterVolumes(vols []volume.Volume, filter filters.Args) (
 		}
 		retVols = daemon.volumes.FilterByUsed(retVols, !danglingOnly)
 	}
+	if filter.Contains("unused-since") {
+		since, err := getTimeFromFilters(filter, "unused-since")
+		if err != nil {
+			return nil, errdefs.InvalidParameter(err)
+		}
+		retVols = daemon.volumes.FilterByUnusedSince(retVols, since)
+	}
 	return retVols, nil
 }
 
@@ -11,4 +11,5 @@ import (
 	_ "github.com/ellcrys/docker/daemon/logger/logentries"
 	_ "github.com/ellcrys/docker/daemon/logger/splunk"
 	_ "github.com/ellcrys/docker/daemon/logger/syslog"
+	_ "github.com/ellcrys/docker/daemon/logger/tee"
 )
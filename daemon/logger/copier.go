@@ -25,11 +25,12 @@ const (
 // Writes are concurrent, so you need implement some sync in your logger.
 type Copier struct {
 	// srcs is map of name -> reader pairs, for example "stdout", "stderr"
-	srcs      map[string]io.Reader
-	dst       Logger
-	copyJobs  sync.WaitGroup
-	closeOnce sync.Once
-	closed    chan struct{}
+	srcs       map[string]io.Reader
+	dst        Logger
+	processing *processingConfig
+	copyJobs   sync.WaitGroup
+	closeOnce  sync.Once
+	closed     chan struct{}
 }
 
 // NewCopier creates a new Copier
@@ -41,6 +42,19 @@ func NewCopier(srcs map[string]io.Reader, dst Logger) *Copier {
 	}
 }
 
+// NewCopierWithLogOpts creates a new Copier, which parses and joins lines as
+// set in the "log-format", "log-fields" and "multiline-*" log options, before
+// passing them to dst.
+func NewCopierWithLogOpts(srcs map[string]io.Reader, dst Logger, cfg map[string]string) (*Copier, error) {
+	processing, err := parseProcessingConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	c := NewCopier(srcs, dst)
+	c.processing = processing
+	return c, nil
+}
+
 // Run starts logs copying
 func (c *Copier) Run() {
 	for src, w := range c.srcs {
@@ -52,6 +66,13 @@ func (c *Copier) Run() {
 func (c *Copier) copySrc(name string, src io.Reader) {
 	defer c.copyJobs.Done()
 
+	logMsg := c.dst.Log
+	if c.processing != nil {
+		p := newLineProcessor(c.processing, c.dst)
+		defer p.Flush()
+		logMsg = p.Log
+	}
+
 	bufSize := defaultBufSize
 	if sizedLogger, ok := c.dst.(SizedLogger); ok {
 		bufSize = sizedLogger.BufSize()
@@ -120,7 +141,7 @@ func (c *Copier) copySrc(name string, src io.Reader) {
 						msg.Timestamp = partialTS
 					}
 
-					if logErr := c.dst.Log(msg); logErr != nil {
+					if logErr := logMsg(msg); logErr != nil {
 						logWritesFailedCount.Inc(1)
 						logrus.Errorf("Failed to log msg %q for logger %s: %s", msg.Line, c.dst.Name(), logErr)
 					}
@@ -153,7 +174,7 @@ func (c *Copier) copySrc(name string, src io.Reader) {
 					ordinal++
 					hasMorePartial = true
 
-					if logErr := c.dst.Log(msg); logErr != nil {
+					if logErr := logMsg(msg); logErr != nil {
 						logWritesFailedCount.Inc(1)
 						logrus.Errorf("Failed to log msg %q for logger %s: %s", msg.Line, c.dst.Name(), logErr)
 					}
@@ -5,9 +5,9 @@ import (
 	"sort"
 	"sync"
 
+	"github.com/docker/go-units"
 	containertypes "github.com/ellcrys/docker/api/types/container"
 	"github.com/ellcrys/docker/pkg/plugingetter"
-	"github.com/docker/go-units"
 	"github.com/pkg/errors"
 )
 
@@ -117,8 +117,12 @@ func GetLogDriver(name string) (Creator, error) {
 }
 
 var builtInLogOpts = map[string]bool{
-	"mode":            true,
-	"max-buffer-size": true,
+	"mode":              true,
+	"max-buffer-size":   true,
+	logFormatKey:        true,
+	logFieldsKey:        true,
+	multilinePatternKey: true,
+	multilineTimeoutKey: true,
 }
 
 // ValidateLogOpts checks the options for the given log driver. The
@@ -143,6 +147,10 @@ func ValidateLogOpts(name string, cfg map[string]string) error {
 		}
 	}
 
+	if _, err := parseProcessingConfig(cfg); err != nil {
+		return err
+	}
+
 	if !factory.driverRegistered(name) {
 		return fmt.Errorf("logger: no log driver named '%s' is registered", name)
 	}
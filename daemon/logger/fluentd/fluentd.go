@@ -163,6 +163,11 @@ func (f *fluentd) Log(msg *logger.Message) error {
 	for k, v := range f.extra {
 		data[k] = v
 	}
+	for _, a := range msg.Attrs {
+		if _, exists := data[a.Key]; !exists {
+			data[a.Key] = a.Value
+		}
+	}
 	if msg.PLogMetaData != nil {
 		data["partial_message"] = "true"
 	}
@@ -179,6 +179,12 @@ func (s *gelfLogger) Log(msg *logger.Message) error {
 		Level:    int32(level),
 		RawExtra: s.rawExtra,
 	}
+	if len(msg.Attrs) > 0 {
+		m.Extra = make(map[string]interface{}, len(msg.Attrs))
+		for _, a := range msg.Attrs {
+			m.Extra["_"+a.Key] = a.Value
+		}
+	}
 	logger.PutMessage(msg)
 
 	if err := s.writer.WriteMessage(&m); err != nil {
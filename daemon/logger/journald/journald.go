@@ -103,6 +103,13 @@ func validateLogOpt(cfg map[string]string) error {
 	return nil
 }
 
+// sourcePriorities maps the streams of a container to the priorities their
+// messages are logged with.
+var sourcePriorities = map[string]journal.Priority{
+	"stdout": journal.PriInfo,
+	"stderr": journal.PriErr,
+}
+
 func (s *journald) Log(msg *logger.Message) error {
 	vars := map[string]string{}
 	for k, v := range s.vars {
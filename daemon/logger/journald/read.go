@@ -151,6 +151,7 @@ import "C"
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 	"unsafe"
@@ -171,7 +172,7 @@ func (s *journald) Close() error {
 	return nil
 }
 
-func (s *journald) drainJournal(logWatcher *logger.LogWatcher, j *C.sd_journal, oldCursor *C.char, untilUnixMicro uint64) (*C.char, bool) {
+func (s *journald) drainJournal(logWatcher *logger.LogWatcher, j *C.sd_journal, oldCursor *C.char, untilUnixMicro uint64, pattern *regexp.Regexp) (*C.char, bool) {
 	var msg, data, cursor *C.char
 	var length C.size_t
 	var stamp C.uint64_t
@@ -206,6 +207,12 @@ drain:
 			// Set up the time and text of the entry.
 			timestamp := time.Unix(int64(stamp)/1000000, (int64(stamp)%1000000)*1000)
 			line := C.GoBytes(unsafe.Pointer(msg), C.int(length))
+			if pattern != nil && !pattern.Match(line) {
+				if C.sd_journal_next(j) <= 0 {
+					break
+				}
+				continue
+			}
 			if partial == 0 {
 				line = append(line, "\n"...)
 			}
@@ -251,7 +258,7 @@ drain:
 	return cursor, done
 }
 
-func (s *journald) followJournal(logWatcher *logger.LogWatcher, j *C.sd_journal, pfd [2]C.int, cursor *C.char, untilUnixMicro uint64) *C.char {
+func (s *journald) followJournal(logWatcher *logger.LogWatcher, j *C.sd_journal, pfd [2]C.int, cursor *C.char, untilUnixMicro uint64, pattern *regexp.Regexp) *C.char {
 	s.mu.Lock()
 	s.readers.readers[logWatcher] = logWatcher
 	if s.closed {
@@ -279,7 +286,7 @@ func (s *journald) followJournal(logWatcher *logger.LogWatcher, j *C.sd_journal,
 			}
 
 			var done bool
-			cursor, done = s.drainJournal(logWatcher, j, cursor, untilUnixMicro)
+			cursor, done = s.drainJournal(logWatcher, j, cursor, untilUnixMicro, pattern)
 
 			if status != 1 || done {
 				// We were notified to stop
@@ -348,6 +355,21 @@ func (s *journald) readLogs(logWatcher *logger.LogWatcher, config logger.ReadCon
 		logWatcher.Err <- fmt.Errorf("error setting journal match")
 		return
 	}
+	// Streams are logged with different priorities, matches on the same
+	// field are ORed together.
+	for _, source := range config.Sources {
+		priority, ok := sourcePriorities[source]
+		if !ok {
+			continue
+		}
+		pmatch := C.CString(fmt.Sprintf("PRIORITY=%d", priority))
+		rc = C.sd_journal_add_match(j, unsafe.Pointer(pmatch), C.strlen(pmatch))
+		C.free(unsafe.Pointer(pmatch))
+		if rc != 0 {
+			logWatcher.Err <- fmt.Errorf("error setting journal match")
+			return
+		}
+	}
 	// If we have a cutoff time, convert it to Unix time once.
 	if !config.Since.IsZero() {
 		nano := config.Since.UnixNano()
@@ -386,7 +408,9 @@ func (s *journald) readLogs(logWatcher *logger.LogWatcher, config logger.ReadCon
 					break
 				}
 			}
-			lines--
+			if config.Pattern == nil || entryMatches(j, config.Pattern) {
+				lines--
+			}
 			// If we're at the start of the journal, or
 			// don't need to back up past any more entries,
 			// stop.
@@ -410,7 +434,7 @@ func (s *journald) readLogs(logWatcher *logger.LogWatcher, config logger.ReadCon
 			return
 		}
 	}
-	cursor, _ = s.drainJournal(logWatcher, j, nil, untilUnixMicro)
+	cursor, _ = s.drainJournal(logWatcher, j, nil, untilUnixMicro, config.Pattern)
 	if config.Follow {
 		// Allocate a descriptor for following the journal, if we'll
 		// need one.  Do it here so that we can report if it fails.
@@ -422,7 +446,7 @@ func (s *journald) readLogs(logWatcher *logger.LogWatcher, config logger.ReadCon
 			if C.pipe(&pipes[0]) == C.int(-1) {
 				logWatcher.Err <- fmt.Errorf("error opening journald close notification pipe")
 			} else {
-				cursor = s.followJournal(logWatcher, j, pipes, cursor, untilUnixMicro)
+				cursor = s.followJournal(logWatcher, j, pipes, cursor, untilUnixMicro, config.Pattern)
 				// Let followJournal handle freeing the journal context
 				// object and closing the channel.
 				following = true
@@ -434,6 +458,18 @@ func (s *journald) readLogs(logWatcher *logger.LogWatcher, config logger.ReadCon
 	return
 }
 
+// entryMatches returns whether the message of the current journal entry
+// matches the pattern.
+func entryMatches(j *C.sd_journal, pattern *regexp.Regexp) bool {
+	var msg *C.char
+	var length C.size_t
+	var partial C.int
+	if C.get_message(j, &msg, &length, &partial) != 0 {
+		return false
+	}
+	return pattern.Match(C.GoBytes(unsafe.Pointer(msg), C.int(length)))
+}
+
 func (s *journald) ReadLogs(config logger.ReadConfig) *logger.LogWatcher {
 	logWatcher := logger.NewLogWatcher()
 	go s.readLogs(logWatcher, config)
@@ -102,7 +102,23 @@ func New(info logger.Info) (logger.Logger, error) {
 
 	buf := bytes.NewBuffer(nil)
 	marshalFunc := func(msg *logger.Message) ([]byte, error) {
-		if err := marshalMessage(msg, extra, buf); err != nil {
+		msgExtra := json.RawMessage(extra)
+		if len(msg.Attrs) > 0 {
+			// attributes extracted from the message, see the "log-format"
+			// log option
+			merged := make(map[string]string, len(attrs)+len(msg.Attrs))
+			for _, a := range msg.Attrs {
+				merged[a.Key] = a.Value
+			}
+			for k, v := range attrs {
+				merged[k] = v
+			}
+			var err error
+			if msgExtra, err = json.Marshal(merged); err != nil {
+				return nil, err
+			}
+		}
+		if err := marshalMessage(msg, msgExtra, buf); err != nil {
 			return nil, err
 		}
 		b := buf.Bytes()
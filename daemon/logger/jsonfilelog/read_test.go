@@ -2,11 +2,13 @@ package jsonfilelog // import "github.com/ellcrys/docker/daemon/logger/jsonfilel
 
 import (
 	"bytes"
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/ellcrys/docker/daemon/logger"
 	"github.com/gotestyourself/gotestyourself/assert"
+	is "github.com/gotestyourself/gotestyourself/assert/cmp"
 	"github.com/gotestyourself/gotestyourself/fs"
 )
 
@@ -62,3 +64,31 @@ func BenchmarkJSONFileLoggerReadLogs(b *testing.B) {
 		}
 	}
 }
+
+func TestJSONFileLoggerReadLogsFiltered(t *testing.T) {
+	tmp := fs.NewDir(t, "jsonfilelog-filtered")
+	defer tmp.Remove()
+
+	l, err := New(logger.Info{LogPath: tmp.Join("container.log")})
+	assert.NilError(t, err)
+	defer l.Close()
+
+	for i, line := range []string{"error: one", "info: two", "error: three", "error: four", "error: five"} {
+		source := "stderr"
+		if i == 4 {
+			source = "stdout"
+		}
+		assert.NilError(t, l.Log(&logger.Message{Line: []byte(line), Source: source, Timestamp: time.Now()}))
+	}
+
+	lw := l.(*JSONFileLogger).ReadLogs(logger.ReadConfig{
+		Tail:    2,
+		Sources: []string{"stderr"},
+		Pattern: regexp.MustCompile("^error"),
+	})
+	var lines []string
+	for msg := range lw.Msg {
+		lines = append(lines, string(msg.Line))
+	}
+	assert.Check(t, is.DeepEqual([]string{"error: three\n", "error: four\n"}, lines))
+}
@@ -8,6 +8,7 @@
 package logger // import "github.com/ellcrys/docker/daemon/logger"
 
 import (
+	"regexp"
 	"sync"
 	"time"
 
@@ -91,6 +92,36 @@ type ReadConfig struct {
 	Until  time.Time
 	Tail   int
 	Follow bool
+	// Sources restricts the messages read to the ones from these sources,
+	// such as "stdout" or "stderr". Messages from all sources are read if
+	// empty.
+	Sources []string
+	// Pattern restricts the messages read to the ones whose line matches.
+	Pattern *regexp.Regexp
+}
+
+// Filtered returns whether the config restricts the messages read by source
+// or pattern. Tail then applies to the messages left by the filters.
+func (c ReadConfig) Filtered() bool {
+	return len(c.Sources) > 0 || c.Pattern != nil
+}
+
+// Matches returns whether the message passes the source and pattern filters
+// of the config.
+func (c ReadConfig) Matches(msg *Message) bool {
+	if len(c.Sources) > 0 {
+		found := false
+		for _, s := range c.Sources {
+			if s == msg.Source {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return c.Pattern == nil || c.Pattern.Match(msg.Line)
 }
 
 // LogReader is the interface for reading log messages for loggers that support reading.
@@ -348,7 +348,7 @@ func (w *LogFile) ReadLogs(config logger.ReadConfig, watcher *logger.LogWatcher)
 
 	notifyRotate := w.notifyRotate.Subscribe()
 	defer w.notifyRotate.Evict(notifyRotate)
-	followLogs(currentFile, watcher, notifyRotate, w.createDecoder, config.Since, config.Until)
+	followLogs(currentFile, watcher, notifyRotate, w.createDecoder, config)
 }
 
 func (w *LogFile) openRotatedFiles(config logger.ReadConfig) (files []*os.File, err error) {
@@ -457,6 +457,11 @@ func newSectionReader(f *os.File) (*io.SectionReader, error) {
 type decodeFunc func() (*logger.Message, error)
 
 func tailFile(f io.ReadSeeker, watcher *logger.LogWatcher, createDecoder makeDecoderFunc, config logger.ReadConfig) {
+	if config.Tail > 0 && config.Filtered() {
+		tailFileFiltered(f, watcher, createDecoder, config)
+		return
+	}
+
 	var rdr io.Reader = f
 	if config.Tail > 0 {
 		ls, err := tailfile.TailFile(f, config.Tail)
@@ -482,6 +487,49 @@ func tailFile(f io.ReadSeeker, watcher *logger.LogWatcher, createDecoder makeDec
 		if !config.Until.IsZero() && msg.Timestamp.After(config.Until) {
 			return
 		}
+		if !config.Matches(msg) {
+			continue
+		}
+		select {
+		case <-watcher.WatchClose():
+			return
+		case watcher.Msg <- msg:
+		}
+	}
+}
+
+// tailFileFiltered sends the last config.Tail messages passing the filters of
+// the config. As matching messages can be anywhere in the file, the whole file
+// is decoded.
+func tailFileFiltered(f io.ReadSeeker, watcher *logger.LogWatcher, createDecoder makeDecoderFunc, config logger.ReadConfig) {
+	tail := make([]*logger.Message, 0, config.Tail)
+	decodeLogLine := createDecoder(f)
+	for {
+		msg, err := decodeLogLine()
+		if err != nil {
+			if errors.Cause(err) != io.EOF {
+				watcher.Err <- err
+				return
+			}
+			break
+		}
+		if !config.Since.IsZero() && msg.Timestamp.Before(config.Since) {
+			continue
+		}
+		if !config.Until.IsZero() && msg.Timestamp.After(config.Until) {
+			break
+		}
+		if !config.Matches(msg) {
+			continue
+		}
+		if len(tail) == config.Tail {
+			copy(tail, tail[1:])
+			tail = tail[:len(tail)-1]
+		}
+		tail = append(tail, msg)
+	}
+
+	for _, msg := range tail {
 		select {
 		case <-watcher.WatchClose():
 			return
@@ -490,7 +538,8 @@ func tailFile(f io.ReadSeeker, watcher *logger.LogWatcher, createDecoder makeDec
 	}
 }
 
-func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan interface{}, createDecoder makeDecoderFunc, since, until time.Time) {
+func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan interface{}, createDecoder makeDecoderFunc, config logger.ReadConfig) {
+	since, until := config.Since, config.Until
 	decodeLogLine := createDecoder(f)
 
 	name := f.Name()
@@ -619,6 +668,9 @@ func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan int
 		if !until.IsZero() && msg.Timestamp.After(until) {
 			return
 		}
+		if !config.Matches(msg) {
+			continue
+		}
 		select {
 		case logWatcher.Msg <- msg:
 		case <-ctx.Done():
@@ -634,6 +686,9 @@ func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan int
 				if !until.IsZero() && msg.Timestamp.After(until) {
 					return
 				}
+				if !config.Matches(msg) {
+					continue
+				}
 				logWatcher.Msg <- msg
 			}
 		}
@@ -0,0 +1,273 @@
+package logger // import "github.com/ellcrys/docker/daemon/logger"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ellcrys/docker/api/types/backend"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Log options controlling how the Copier processes lines before they are
+// passed to the log driver.
+const (
+	logFormatKey        = "log-format"
+	logFieldsKey        = "log-fields"
+	multilinePatternKey = "multiline-pattern"
+	multilineTimeoutKey = "multiline-timeout"
+)
+
+const (
+	logFormatJSON   = "json"
+	logFormatLogfmt = "logfmt"
+
+	// defaultMultilineTimeout is how long a multiline record is held waiting
+	// for more lines, before it is logged.
+	defaultMultilineTimeout = time.Second
+	// maxMultilineSize is the size over which a multiline record is logged
+	// even if more lines belong to it.
+	maxMultilineSize = 1024 * 1024
+)
+
+// processingConfig holds the line processing set in the log options.
+type processingConfig struct {
+	parse            func(line []byte, fields map[string]bool) []backend.LogAttr
+	fields           map[string]bool
+	multiline        *regexp.Regexp
+	multilineTimeout time.Duration
+}
+
+// parseProcessingConfig returns the line processing set in the log options,
+// or nil if none is.
+func parseProcessingConfig(cfg map[string]string) (*processingConfig, error) {
+	var p processingConfig
+	switch format := cfg[logFormatKey]; format {
+	case "":
+	case logFormatJSON:
+		p.parse = parseJSONFields
+	case logFormatLogfmt:
+		p.parse = parseLogfmtFields
+	default:
+		return nil, fmt.Errorf("logger: unsupported log-format '%s', supported formats are %s and %s", format, logFormatJSON, logFormatLogfmt)
+	}
+
+	if s, ok := cfg[logFieldsKey]; ok {
+		if p.parse == nil {
+			return nil, fmt.Errorf("logger: %s option requires the %s option", logFieldsKey, logFormatKey)
+		}
+		p.fields = make(map[string]bool)
+		for _, f := range strings.Split(s, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				p.fields[f] = true
+			}
+		}
+	}
+
+	if s, ok := cfg[multilinePatternKey]; ok {
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "logger: invalid %s", multilinePatternKey)
+		}
+		p.multiline = re
+		p.multilineTimeout = defaultMultilineTimeout
+	}
+	if s, ok := cfg[multilineTimeoutKey]; ok {
+		if p.multiline == nil {
+			return nil, fmt.Errorf("logger: %s option requires the %s option", multilineTimeoutKey, multilinePatternKey)
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "logger: invalid %s", multilineTimeoutKey)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("logger: %s must be positive", multilineTimeoutKey)
+		}
+		p.multilineTimeout = d
+	}
+
+	if p.parse == nil && p.multiline == nil {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+// lineProcessor parses and joins the messages of a single source, before
+// passing them to the log driver.
+type lineProcessor struct {
+	cfg *processingConfig
+	dst Logger
+
+	mu      sync.Mutex
+	pending *Message
+	timer   *time.Timer
+}
+
+func newLineProcessor(cfg *processingConfig, dst Logger) *lineProcessor {
+	return &lineProcessor{cfg: cfg, dst: dst}
+}
+
+// Log processes the message. Multiline records are held until their next
+// record starts, or until no lines were added for the multiline timeout.
+func (p *lineProcessor) Log(msg *Message) error {
+	if p.cfg.multiline == nil {
+		return p.log(msg)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// partial messages are too long to be part of a multiline record
+	if msg.PLogMetaData != nil {
+		p.flushLocked()
+		return p.log(msg)
+	}
+
+	if p.pending != nil && !p.cfg.multiline.Match(msg.Line) && len(p.pending.Line)+len(msg.Line) < maxMultilineSize {
+		p.pending.Line = append(p.pending.Line, '\n')
+		p.pending.Line = append(p.pending.Line, msg.Line...)
+		PutMessage(msg)
+		p.timer.Reset(p.cfg.multilineTimeout)
+		return nil
+	}
+
+	p.flushLocked()
+	p.pending = msg
+	if p.timer == nil {
+		p.timer = time.AfterFunc(p.cfg.multilineTimeout, p.timeout)
+	} else {
+		p.timer.Reset(p.cfg.multilineTimeout)
+	}
+	return nil
+}
+
+func (p *lineProcessor) timeout() {
+	p.mu.Lock()
+	p.flushLocked()
+	p.mu.Unlock()
+}
+
+// Flush logs the multiline record being held, if any.
+func (p *lineProcessor) Flush() {
+	p.mu.Lock()
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	p.flushLocked()
+	p.mu.Unlock()
+}
+
+func (p *lineProcessor) flushLocked() {
+	if p.pending == nil {
+		return
+	}
+	msg := p.pending
+	p.pending = nil
+	if err := p.log(msg); err != nil {
+		logWritesFailedCount.Inc(1)
+		logrus.Errorf("Failed to log msg for logger %s: %s", p.dst.Name(), err)
+	}
+}
+
+func (p *lineProcessor) log(msg *Message) error {
+	if p.cfg.parse != nil && msg.PLogMetaData == nil {
+		msg.Attrs = append(msg.Attrs, p.cfg.parse(msg.Line, p.cfg.fields)...)
+	}
+	return p.dst.Log(msg)
+}
+
+// parseJSONFields returns the top-level fields of a JSON object line. Values
+// which are not strings are kept in their JSON form. A nil fields returns
+// all fields.
+func parseJSONFields(line []byte, fields map[string]bool) []backend.LogAttr {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(line, &obj); err != nil {
+		return nil
+	}
+
+	var attrs []backend.LogAttr
+	for k, raw := range obj {
+		if fields != nil && !fields[k] {
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			s = string(raw)
+		}
+		attrs = append(attrs, backend.LogAttr{Key: k, Value: s})
+	}
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].Key < attrs[j].Key })
+	return attrs
+}
+
+// parseLogfmtFields returns the key=value pairs of a logfmt line, in the
+// order they appear in. Values may be double-quoted, and keys without a value
+// are set to "true". A nil fields returns all fields.
+func parseLogfmtFields(line []byte, fields map[string]bool) []backend.LogAttr {
+	var attrs []backend.LogAttr
+	for len(line) > 0 {
+		line = bytes.TrimLeft(line, logfmtSpace)
+		if len(line) == 0 {
+			break
+		}
+
+		end := bytes.IndexAny(line, "="+logfmtSpace)
+		if end < 0 {
+			end = len(line)
+		}
+		key := string(line[:end])
+		line = line[end:]
+
+		value := "true"
+		if len(line) > 0 && line[0] == '=' {
+			line = line[1:]
+			var ok bool
+			value, line, ok = readLogfmtValue(line)
+			if !ok {
+				// not logfmt, don't promote anything from this line
+				return nil
+			}
+		}
+		if key == "" || (fields != nil && !fields[key]) {
+			continue
+		}
+		attrs = append(attrs, backend.LogAttr{Key: key, Value: value})
+	}
+	return attrs
+}
+
+// logfmtSpace separates the pairs of a logfmt line, or of the lines of a
+// multiline record
+const logfmtSpace = " \t\r\n"
+
+func readLogfmtValue(line []byte) (string, []byte, bool) {
+	if len(line) == 0 || line[0] != '"' {
+		end := bytes.IndexAny(line, logfmtSpace)
+		if end < 0 {
+			end = len(line)
+		}
+		return string(line[:end]), line[end:], true
+	}
+
+	// find the closing quote, skipping escaped characters
+	for i := 1; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '"':
+			value, err := strconv.Unquote(string(line[:i+1]))
+			if err != nil {
+				return "", nil, false
+			}
+			return value, line[i+1:], true
+		}
+	}
+	return "", nil, false
+}
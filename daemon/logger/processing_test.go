@@ -0,0 +1,181 @@
+package logger // import "github.com/ellcrys/docker/daemon/logger"
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ellcrys/docker/api/types/backend"
+)
+
+type collectingLogger struct {
+	mu   sync.Mutex
+	msgs []backend.LogMessage
+}
+
+func (l *collectingLogger) Log(m *Message) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	msg := *m.AsLogMessage()
+	msg.Line = append([]byte(nil), m.Line...)
+	l.msgs = append(l.msgs, msg)
+	return nil
+}
+
+func (l *collectingLogger) Close() error { return nil }
+
+func (l *collectingLogger) Name() string { return "collecting" }
+
+func (l *collectingLogger) lines() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var lines []string
+	for _, m := range l.msgs {
+		lines = append(lines, string(m.Line))
+	}
+	return lines
+}
+
+func TestParseProcessingConfig(t *testing.T) {
+	for _, tc := range []struct {
+		cfg map[string]string
+		err string
+	}{
+		{cfg: map[string]string{"log-format": "json", "log-fields": "level,msg"}},
+		{cfg: map[string]string{"log-format": "logfmt", "multiline-pattern": `^\S`, "multiline-timeout": "500ms"}},
+		{cfg: map[string]string{"log-format": "xml"}, err: "unsupported log-format"},
+		{cfg: map[string]string{"log-fields": "level"}, err: "requires the log-format option"},
+		{cfg: map[string]string{"multiline-pattern": "("}, err: "invalid multiline-pattern"},
+		{cfg: map[string]string{"multiline-timeout": "1s"}, err: "requires the multiline-pattern option"},
+		{cfg: map[string]string{"multiline-pattern": "^a", "multiline-timeout": "0s"}, err: "must be positive"},
+	} {
+		_, err := parseProcessingConfig(tc.cfg)
+		if tc.err == "" {
+			if err != nil {
+				t.Fatalf("unexpected error for %v: %v", tc.cfg, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Fatalf("expected error containing %q for %v, got: %v", tc.err, tc.cfg, err)
+		}
+	}
+
+	if p, err := parseProcessingConfig(map[string]string{"max-size": "10m"}); p != nil || err != nil {
+		t.Fatalf("expected no processing, got: %v, %v", p, err)
+	}
+}
+
+func TestParseJSONFields(t *testing.T) {
+	line := []byte(`{"level":"error","msg":"failed","code":42,"ctx":{"user":"u"}}`)
+	expected := []backend.LogAttr{
+		{Key: "code", Value: "42"},
+		{Key: "ctx", Value: `{"user":"u"}`},
+		{Key: "level", Value: "error"},
+		{Key: "msg", Value: "failed"},
+	}
+	if attrs := parseJSONFields(line, nil); !reflect.DeepEqual(attrs, expected) {
+		t.Fatalf("expected %v, got %v", expected, attrs)
+	}
+
+	expected = []backend.LogAttr{{Key: "level", Value: "error"}}
+	if attrs := parseJSONFields(line, map[string]bool{"level": true, "other": true}); !reflect.DeepEqual(attrs, expected) {
+		t.Fatalf("expected %v, got %v", expected, attrs)
+	}
+
+	if attrs := parseJSONFields([]byte("not json"), nil); attrs != nil {
+		t.Fatalf("expected no attributes, got %v", attrs)
+	}
+}
+
+func TestParseLogfmtFields(t *testing.T) {
+	line := []byte(`level=warn msg="disk \"full\"" retry  empty= dur=1.5s`)
+	expected := []backend.LogAttr{
+		{Key: "level", Value: "warn"},
+		{Key: "msg", Value: `disk "full"`},
+		{Key: "retry", Value: "true"},
+		{Key: "empty", Value: ""},
+		{Key: "dur", Value: "1.5s"},
+	}
+	if attrs := parseLogfmtFields(line, nil); !reflect.DeepEqual(attrs, expected) {
+		t.Fatalf("expected %v, got %v", expected, attrs)
+	}
+
+	expected = []backend.LogAttr{{Key: "msg", Value: `disk "full"`}}
+	if attrs := parseLogfmtFields(line, map[string]bool{"msg": true}); !reflect.DeepEqual(attrs, expected) {
+		t.Fatalf("expected %v, got %v", expected, attrs)
+	}
+
+	if attrs := parseLogfmtFields([]byte(`msg="unterminated`), nil); attrs != nil {
+		t.Fatalf("expected no attributes, got %v", attrs)
+	}
+}
+
+func TestCopierMultiline(t *testing.T) {
+	var stdout bytes.Buffer
+	stdout.WriteString("2018-01-01 ERROR boom\n")
+	stdout.WriteString("java.lang.RuntimeException: boom\n")
+	stdout.WriteString("\tat Main.main(Main.java:1)\n")
+	stdout.WriteString("2018-01-01 INFO recovered\n")
+
+	dst := &collectingLogger{}
+	c, err := NewCopierWithLogOpts(map[string]io.Reader{"stdout": &stdout}, dst, map[string]string{
+		"multiline-pattern": `^\d{4}-\d{2}-\d{2} `,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Run()
+	c.Wait()
+
+	expected := []string{
+		"2018-01-01 ERROR boom\njava.lang.RuntimeException: boom\n\tat Main.main(Main.java:1)",
+		"2018-01-01 INFO recovered",
+	}
+	if lines := dst.lines(); !reflect.DeepEqual(lines, expected) {
+		t.Fatalf("expected %q, got %q", expected, lines)
+	}
+}
+
+func TestLineProcessorMultilineTimeout(t *testing.T) {
+	dst := &collectingLogger{}
+	cfg, err := parseProcessingConfig(map[string]string{
+		"log-format":        "logfmt",
+		"multiline-pattern": `^level=`,
+		"multiline-timeout": "10ms",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := newLineProcessor(cfg, dst)
+
+	for _, line := range []string{"level=error msg=boom", "  trace"} {
+		msg := NewMessage()
+		msg.Line = append(msg.Line, line...)
+		if err := p.Log(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(dst.lines()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+	if len(dst.msgs) != 1 {
+		t.Fatalf("expected the record to be flushed, got %d messages", len(dst.msgs))
+	}
+	if line := string(dst.msgs[0].Line); line != "level=error msg=boom\n  trace" {
+		t.Fatalf("unexpected line: %q", line)
+	}
+	expected := []backend.LogAttr{{Key: "level", Value: "error"}, {Key: "msg", Value: "boom"}, {Key: "trace", Value: "true"}}
+	if !reflect.DeepEqual(dst.msgs[0].Attrs, expected) {
+		t.Fatalf("expected %v, got %v", expected, dst.msgs[0].Attrs)
+	}
+}
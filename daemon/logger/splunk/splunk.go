@@ -346,6 +346,7 @@ func (l *splunkLoggerInline) Log(msg *logger.Message) error {
 	event := *l.nullEvent
 	event.Line = string(msg.Line)
 	event.Source = msg.Source
+	event.Attrs = l.eventAttrs(msg)
 
 	message.Event = &event
 	logger.PutMessage(msg)
@@ -364,12 +365,29 @@ func (l *splunkLoggerJSON) Log(msg *logger.Message) error {
 	}
 
 	event.Source = msg.Source
+	event.Attrs = l.eventAttrs(msg)
 
 	message.Event = &event
 	logger.PutMessage(msg)
 	return l.queueMessageAsync(message)
 }
 
+// eventAttrs returns the attributes of the logger, along with the ones
+// extracted from the message.
+func (l *splunkLoggerInline) eventAttrs(msg *logger.Message) map[string]string {
+	if len(msg.Attrs) == 0 {
+		return l.nullEvent.Attrs
+	}
+	attrs := make(map[string]string, len(l.nullEvent.Attrs)+len(msg.Attrs))
+	for _, a := range msg.Attrs {
+		attrs[a.Key] = a.Value
+	}
+	for k, v := range l.nullEvent.Attrs {
+		attrs[k] = v
+	}
+	return attrs
+}
+
 func (l *splunkLoggerRaw) Log(msg *logger.Message) error {
 	// empty or whitespace-only messages are not accepted by HEC
 	if strings.TrimSpace(string(msg.Line)) == "" {
@@ -0,0 +1,243 @@
+// Package tee provides the log driver that forwards container logs to
+// several other log drivers at once.
+package tee // import "github.com/ellcrys/docker/daemon/logger/tee"
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/go-units"
+	containertypes "github.com/ellcrys/docker/api/types/container"
+	"github.com/ellcrys/docker/daemon/logger"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Name is the name of the tee log driver.
+const Name = "tee"
+
+const (
+	driversKey       = "tee-drivers"
+	maxBufferSizeKey = "max-buffer-size"
+	// driver options are passed as "<driver>.<option>=<value>"
+	driverOptSep = "."
+)
+
+type child struct {
+	name string
+	l    logger.Logger
+}
+
+// teeLogger forwards each message to all of its children. Every child is
+// wrapped in a ring buffer, so that a slow or failing driver neither blocks
+// the container's output nor the other drivers.
+type teeLogger struct {
+	children []child
+}
+
+type teeWithReader struct {
+	*teeLogger
+	reader logger.LogReader
+}
+
+func init() {
+	if err := logger.RegisterLogDriver(Name, New); err != nil {
+		logrus.Fatal(err)
+	}
+	if err := logger.RegisterLogOptValidator(Name, ValidateLogOpt); err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+// New creates a tee logger, starting all of the drivers set in the
+// "tee-drivers" option. Logs are read back from the first of these drivers
+// which supports reading.
+func New(info logger.Info) (logger.Logger, error) {
+	drivers := Drivers(info.Config)
+	if len(drivers) == 0 {
+		return nil, fmt.Errorf("%s: missing required option %s", Name, driversKey)
+	}
+
+	t := &teeLogger{}
+	var reader logger.LogReader
+	for _, name := range drivers {
+		l, err := newChild(name, info)
+		if err != nil {
+			t.Close()
+			return nil, errors.Wrapf(err, "%s: failed to initialize log driver %s", Name, name)
+		}
+		t.children = append(t.children, child{name: name, l: l})
+		if r, ok := l.(logger.LogReader); ok && reader == nil {
+			reader = r
+		}
+	}
+
+	if reader != nil {
+		return &teeWithReader{teeLogger: t, reader: reader}, nil
+	}
+	return t, nil
+}
+
+func newChild(name string, info logger.Info) (logger.Logger, error) {
+	initDriver, err := logger.GetLogDriver(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := driverOpts(info.Config, name)
+	bufferSize := int64(-1)
+	if s, ok := cfg[maxBufferSizeKey]; ok {
+		bufferSize, err = units.RAMInBytes(s)
+		if err != nil {
+			return nil, err
+		}
+		delete(cfg, maxBufferSizeKey)
+	}
+
+	childInfo := info
+	childInfo.Config = cfg
+	l, err := initDriver(childInfo)
+	if err != nil {
+		return nil, err
+	}
+	return logger.NewRingLogger(l, childInfo, bufferSize), nil
+}
+
+// Log copies the message to every child. The message is returned to the
+// pool once all copies are queued.
+func (t *teeLogger) Log(msg *logger.Message) error {
+	var errs []string
+	for _, c := range t.children {
+		if err := c.l.Log(copyMessage(msg)); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", c.name, err))
+		}
+	}
+	logger.PutMessage(msg)
+	if len(errs) > 0 {
+		return fmt.Errorf("%s: error logging message: %s", Name, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func copyMessage(msg *logger.Message) *logger.Message {
+	m := logger.NewMessage()
+	m.Line = append(m.Line, msg.Line...)
+	m.Source = msg.Source
+	m.Timestamp = msg.Timestamp
+	m.Attrs = msg.Attrs
+	if msg.PLogMetaData != nil {
+		md := *msg.PLogMetaData
+		m.PLogMetaData = &md
+	}
+	m.Err = msg.Err
+	return m
+}
+
+// Name returns the name of the tee log driver.
+func (t *teeLogger) Name() string {
+	return Name
+}
+
+// Close closes all of the children, flushing the messages still buffered.
+func (t *teeLogger) Close() error {
+	var errs []string
+	for _, c := range t.children {
+		if err := c.l.Close(); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", c.name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s: error closing log drivers: %s", Name, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// ReadLogs reads the logs from the first child supporting reading.
+func (t *teeWithReader) ReadLogs(cfg logger.ReadConfig) *logger.LogWatcher {
+	return t.reader.ReadLogs(cfg)
+}
+
+// Drivers returns the names of the drivers set in the "tee-drivers" option.
+func Drivers(cfg map[string]string) []string {
+	var drivers []string
+	for _, name := range strings.Split(cfg[driversKey], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			drivers = append(drivers, name)
+		}
+	}
+	return drivers
+}
+
+// HasDriver returns whether the driver is one of the tee's children.
+func HasDriver(cfg map[string]string, driver string) bool {
+	for _, name := range Drivers(cfg) {
+		if name == driver {
+			return true
+		}
+	}
+	return false
+}
+
+// driverOpts returns the options meant for the named driver, with their
+// prefix stripped.
+func driverOpts(cfg map[string]string, driver string) map[string]string {
+	prefix := driver + driverOptSep
+	opts := make(map[string]string)
+	for k, v := range cfg {
+		if strings.HasPrefix(k, prefix) {
+			opts[strings.TrimPrefix(k, prefix)] = v
+		}
+	}
+	return opts
+}
+
+// ValidateLogOpt looks for tee specific log options, and validates the
+// options of each driver with the driver's own validator.
+func ValidateLogOpt(cfg map[string]string) error {
+	drivers := Drivers(cfg)
+	if len(drivers) == 0 {
+		return fmt.Errorf("missing required log opt '%s' for log driver %s", driversKey, Name)
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range drivers {
+		switch name {
+		case Name, "none":
+			return fmt.Errorf("log driver %s cannot be used with log driver %s", name, Name)
+		}
+		if seen[name] {
+			return fmt.Errorf("log driver %s is set more than once in '%s'", name, driversKey)
+		}
+		seen[name] = true
+	}
+
+	for key := range cfg {
+		if key == driversKey {
+			continue
+		}
+		if !hasDriverPrefix(key, drivers) {
+			return fmt.Errorf("unknown log opt '%s' for %s log driver", key, Name)
+		}
+	}
+
+	for _, name := range drivers {
+		opts := driverOpts(cfg, name)
+		if _, ok := opts["mode"]; ok {
+			return fmt.Errorf("log opt 'mode' is not supported for the drivers of log driver %s, they are always %s", Name, containertypes.LogModeNonBlock)
+		}
+		opts["mode"] = string(containertypes.LogModeNonBlock)
+		if err := logger.ValidateLogOpts(name, opts); err != nil {
+			return errors.Wrapf(err, "invalid log opts for log driver %s", name)
+		}
+	}
+	return nil
+}
+
+func hasDriverPrefix(key string, drivers []string) bool {
+	for _, name := range drivers {
+		if strings.HasPrefix(key, name+driverOptSep) {
+			return true
+		}
+	}
+	return false
+}
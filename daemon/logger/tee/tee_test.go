@@ -0,0 +1,141 @@
+package tee // import "github.com/ellcrys/docker/daemon/logger/tee"
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ellcrys/docker/daemon/logger"
+	"github.com/gotestyourself/gotestyourself/assert"
+	is "github.com/gotestyourself/gotestyourself/assert/cmp"
+)
+
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+	opts  map[string]string
+	block chan struct{}
+}
+
+func (l *recordingLogger) Log(msg *logger.Message) error {
+	if l.block != nil {
+		<-l.block
+	}
+	l.mu.Lock()
+	l.lines = append(l.lines, string(msg.Line))
+	l.mu.Unlock()
+	logger.PutMessage(msg)
+	return nil
+}
+
+func (l *recordingLogger) Name() string { return "recording" }
+
+func (l *recordingLogger) Close() error { return nil }
+
+func (l *recordingLogger) recorded() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.lines...)
+}
+
+type readingLogger struct {
+	*recordingLogger
+}
+
+func (l *readingLogger) ReadLogs(logger.ReadConfig) *logger.LogWatcher {
+	return logger.NewLogWatcher()
+}
+
+// the loggers started by the test drivers, set up by each test
+var fast, slow, reading *recordingLogger
+
+func init() {
+	logger.RegisterLogDriver("tee-test-fast", func(info logger.Info) (logger.Logger, error) {
+		fast.opts = info.Config
+		return fast, nil
+	})
+	logger.RegisterLogDriver("tee-test-slow", func(info logger.Info) (logger.Logger, error) {
+		slow.opts = info.Config
+		return slow, nil
+	})
+	logger.RegisterLogDriver("tee-test-reading", func(info logger.Info) (logger.Logger, error) {
+		return &readingLogger{reading}, nil
+	})
+}
+
+func setupTestDrivers() {
+	fast = &recordingLogger{}
+	slow = &recordingLogger{block: make(chan struct{})}
+	reading = &recordingLogger{}
+}
+
+func TestTeeLoggerFanOut(t *testing.T) {
+	setupTestDrivers()
+	l, err := New(logger.Info{Config: map[string]string{
+		driversKey:             "tee-test-slow,tee-test-fast",
+		"tee-test-fast.foo":    "bar",
+		"tee-test-slow.foo":    "baz",
+		"tee-test-other.other": "ignored",
+	}})
+	assert.NilError(t, err)
+	_, ok := l.(logger.LogReader)
+	assert.Check(t, !ok, "no child supports reading")
+	assert.Check(t, is.DeepEqual(map[string]string{"foo": "bar"}, fast.opts))
+	assert.Check(t, is.DeepEqual(map[string]string{"foo": "baz"}, slow.opts))
+
+	for _, line := range []string{"1", "2"} {
+		msg := logger.NewMessage()
+		msg.Line = append(msg.Line, line...)
+		assert.NilError(t, l.Log(msg))
+	}
+
+	// the blocked child must not hold back the others
+	waitForLines(fast, 2)
+	assert.Check(t, is.DeepEqual([]string{"1", "2"}, fast.recorded()))
+	assert.Check(t, is.Len(slow.recorded(), 0))
+
+	close(slow.block)
+	waitForLines(slow, 2)
+	assert.Check(t, is.DeepEqual([]string{"1", "2"}, slow.recorded()))
+	assert.NilError(t, l.Close())
+}
+
+func waitForLines(l *recordingLogger, n int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for len(l.recorded()) < n && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTeeLoggerReadsFromReader(t *testing.T) {
+	setupTestDrivers()
+	l, err := New(logger.Info{Config: map[string]string{driversKey: "tee-test-fast, tee-test-reading"}})
+	assert.NilError(t, err)
+	defer l.Close()
+
+	_, ok := l.(logger.LogReader)
+	assert.Check(t, ok)
+}
+
+func TestValidateLogOpt(t *testing.T) {
+	for _, tc := range []struct {
+		cfg map[string]string
+		err string
+	}{
+		{cfg: map[string]string{driversKey: "tee-test-fast,tee-test-slow", "tee-test-fast.max-buffer-size": "1m"}},
+		{cfg: map[string]string{}, err: "missing required log opt"},
+		{cfg: map[string]string{driversKey: "tee-test-fast,tee"}, err: "cannot be used with log driver tee"},
+		{cfg: map[string]string{driversKey: "tee-test-fast,tee-test-fast"}, err: "more than once"},
+		{cfg: map[string]string{driversKey: "tee-test-fast", "foo": "bar"}, err: "unknown log opt 'foo'"},
+		{cfg: map[string]string{driversKey: "tee-test-fast", "tee-test-fast.mode": "blocking"}, err: "'mode' is not supported"},
+		{cfg: map[string]string{driversKey: "tee-test-fast", "tee-test-fast.max-buffer-size": "lots"}, err: "max-buffer-size"},
+		{cfg: map[string]string{driversKey: "tee-test-missing"}, err: "no log driver named 'tee-test-missing'"},
+	} {
+		err := ValidateLogOpt(tc.cfg)
+		if tc.err == "" {
+			assert.Check(t, err, tc.cfg)
+			continue
+		}
+		assert.Check(t, is.ErrorContains(err, tc.err), tc.cfg)
+	}
+}
@@ -2,6 +2,7 @@ package daemon // import "github.com/ellcrys/docker/daemon"
 
 import (
 	"context"
+	"regexp"
 	"strconv"
 	"time"
 
@@ -32,6 +33,25 @@ func (daemon *Daemon) ContainerLogs(ctx context.Context, containerName string, c
 	if !(config.ShowStdout || config.ShowStderr) {
 		return nil, false, errdefs.InvalidParameter(errors.New("You must choose at least one stream"))
 	}
+	var sources []string
+	if !(config.ShowStdout && config.ShowStderr) {
+		if config.ShowStdout {
+			sources = []string{"stdout"}
+		} else {
+			sources = []string{"stderr"}
+		}
+	}
+	var pattern *regexp.Regexp
+	if config.Pattern != "" {
+		expr := config.Pattern
+		if !config.Regex {
+			expr = regexp.QuoteMeta(expr)
+		}
+		var err error
+		if pattern, err = regexp.Compile(expr); err != nil {
+			return nil, false, errdefs.InvalidParameter(errors.Wrap(err, "invalid logs pattern"))
+		}
+	}
 	container, err := daemon.GetContainer(containerName)
 	if err != nil {
 		return nil, false, err
@@ -89,10 +109,12 @@ func (daemon *Daemon) ContainerLogs(ctx context.Context, containerName string, c
 	}
 
 	readConfig := logger.ReadConfig{
-		Since:  since,
-		Until:  until,
-		Tail:   tailLines,
-		Follow: follow,
+		Since:   since,
+		Until:   until,
+		Tail:    tailLines,
+		Follow:  follow,
+		Sources: sources,
+		Pattern: pattern,
 	}
 
 	logs := logReader.ReadLogs(readConfig)
@@ -142,6 +164,10 @@ func (daemon *Daemon) ContainerLogs(ctx context.Context, containerName string, c
 					lg.Debug("end logs")
 					return
 				}
+				// not all log drivers apply the filters when reading
+				if !readConfig.Matches(msg) {
+					continue
+				}
 				m := msg.AsLogMessage() // just a pointer conversion, does not copy data
 
 				// there could be a case where the reader stops accepting
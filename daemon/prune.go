@@ -280,24 +280,30 @@ func (daemon *Daemon) NetworksPrune(ctx context.Context, pruneFilters filters.Ar
 }
 
 func getUntilFromPruneFilters(pruneFilters filters.Args) (time.Time, error) {
-	until := time.Time{}
-	if !pruneFilters.Contains("until") {
-		return until, nil
+	return getTimeFromFilters(pruneFilters, "until")
+}
+
+// getTimeFromFilters returns the time of the timestamp or duration of the
+// filter with the given name, or the zero time if the filter is not set.
+func getTimeFromFilters(args filters.Args, name string) (time.Time, error) {
+	t := time.Time{}
+	if !args.Contains(name) {
+		return t, nil
 	}
-	untilFilters := pruneFilters.Get("until")
-	if len(untilFilters) > 1 {
-		return until, fmt.Errorf("more than one until filter specified")
+	values := args.Get(name)
+	if len(values) > 1 {
+		return t, fmt.Errorf("more than one %s filter specified", name)
 	}
-	ts, err := timetypes.GetTimestamp(untilFilters[0], time.Now())
+	ts, err := timetypes.GetTimestamp(values[0], time.Now())
 	if err != nil {
-		return until, err
+		return t, err
 	}
 	seconds, nanoseconds, err := timetypes.ParseTimestamps(ts, 0)
 	if err != nil {
-		return until, err
+		return t, err
 	}
-	until = time.Unix(seconds, nanoseconds)
-	return until, nil
+	t = time.Unix(seconds, nanoseconds)
+	return t, nil
 }
 
 func matchLabels(pruneFilters filters.Args, labels map[string]string) bool {
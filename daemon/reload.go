@@ -21,6 +21,8 @@ import (
 // - Daemon labels
 // - Insecure registries
 // - Registry mirrors
+// - Registry host mirrors
+// - Registry rewrites
 // - Daemon live restore
 func (daemon *Daemon) Reload(conf *config.Config) (err error) {
 	daemon.configStore.Lock()
@@ -61,6 +63,12 @@ func (daemon *Daemon) Reload(conf *config.Config) (err error) {
 	if err := daemon.reloadRegistryMirrors(conf, attributes); err != nil {
 		return err
 	}
+	if err := daemon.reloadRegistryHostMirrors(conf, attributes); err != nil {
+		return err
+	}
+	if err := daemon.reloadRegistryRewrites(conf, attributes); err != nil {
+		return err
+	}
 	if err := daemon.reloadLiveRestore(conf, attributes); err != nil {
 		return err
 	}
@@ -293,6 +301,56 @@ func (daemon *Daemon) reloadRegistryMirrors(conf *config.Config, attributes map[
 	return nil
 }
 
+// reloadRegistryHostMirrors updates configuration with the mirrors of each
+// registry and updates the passed attributes
+func (daemon *Daemon) reloadRegistryHostMirrors(conf *config.Config, attributes map[string]string) error {
+	// update corresponding configuration
+	if conf.IsValueSet("registry-host-mirrors") {
+		daemon.configStore.HostMirrors = conf.HostMirrors
+		if err := daemon.RegistryService.LoadHostMirrors(conf.HostMirrors); err != nil {
+			return err
+		}
+	}
+
+	// prepare reload event attributes with updatable configurations
+	if daemon.configStore.HostMirrors != nil {
+		mirrors, err := json.Marshal(daemon.configStore.HostMirrors)
+		if err != nil {
+			return err
+		}
+		attributes["registry-host-mirrors"] = string(mirrors)
+	} else {
+		attributes["registry-host-mirrors"] = "[]"
+	}
+
+	return nil
+}
+
+// reloadRegistryRewrites updates configuration with the rewrites of the
+// references of the images pulled and updates the passed attributes
+func (daemon *Daemon) reloadRegistryRewrites(conf *config.Config, attributes map[string]string) error {
+	// update corresponding configuration
+	if conf.IsValueSet("registry-rewrites") {
+		daemon.configStore.Rewrites = conf.Rewrites
+		if err := daemon.RegistryService.LoadRewrites(conf.Rewrites); err != nil {
+			return err
+		}
+	}
+
+	// prepare reload event attributes with updatable configurations
+	if daemon.configStore.Rewrites != nil {
+		rewrites, err := json.Marshal(daemon.configStore.Rewrites)
+		if err != nil {
+			return err
+		}
+		attributes["registry-rewrites"] = string(rewrites)
+	} else {
+		attributes["registry-rewrites"] = "[]"
+	}
+
+	return nil
+}
+
 // reloadLiveRestore updates configuration with live retore option
 // and updates the passed attributes
 func (daemon *Daemon) reloadLiveRestore(conf *config.Config, attributes map[string]string) error {
@@ -201,6 +201,38 @@ func TestDaemonReloadMirrors(t *testing.T) {
 	}
 }
 
+func TestDaemonReloadHostMirrors(t *testing.T) {
+	daemon := &Daemon{
+		imageService: images.NewImageService(images.ImageServiceConfig{}),
+	}
+	var err error
+	daemon.RegistryService, err = registry.NewService(registry.ServiceOptions{
+		HostMirrors: []string{"quay.io=https://mirror.test1.com"},
+	})
+	assert.NilError(t, err)
+	daemon.configStore = &config.Config{}
+
+	newConfig := &config.Config{
+		CommonConfig: config.CommonConfig{
+			ServiceOptions: registry.ServiceOptions{
+				HostMirrors: []string{"registry.corp:5000=https://mirror.test2.com"},
+			},
+			ValuesSet: map[string]interface{}{
+				"registry-host-mirrors": []string{"registry.corp:5000=https://mirror.test2.com"},
+			},
+		},
+	}
+	assert.NilError(t, daemon.Reload(newConfig))
+
+	registryService := daemon.RegistryService.ServiceConfig()
+	_, ok := registryService.IndexConfigs["quay.io"]
+	assert.Check(t, !ok, "expected the mirrors of quay.io to be removed")
+	assert.Check(t, is.DeepEqual(registryService.IndexConfigs["registry.corp:5000"].Mirrors, []string{"https://mirror.test2.com/"}))
+
+	newConfig.HostMirrors = []string{"registry.corp:5000=mirror.test2.com"}
+	assert.Check(t, is.ErrorContains(daemon.Reload(newConfig), "invalid mirror"))
+}
+
 func TestDaemonReloadInsecureRegistries(t *testing.T) {
 	daemon := &Daemon{
 		imageService: images.NewImageService(images.ImageServiceConfig{}),
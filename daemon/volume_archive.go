@@ -0,0 +1,118 @@
+package daemon // import "github.com/ellcrys/docker/daemon"
+
+import (
+	"io"
+
+	"github.com/ellcrys/docker/api/types"
+	"github.com/ellcrys/docker/errdefs"
+	"github.com/ellcrys/docker/pkg/archive"
+	"github.com/ellcrys/docker/pkg/chrootarchive"
+	"github.com/ellcrys/docker/pkg/stringid"
+	"github.com/ellcrys/docker/volume"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// VolumeExport writes a tar archive of the content of the volume to out,
+// compressed with compression ("", "none", "gzip" or "zstd"). The ownership
+// of the files is mapped from the remapped root of the daemon.
+func (daemon *Daemon) VolumeExport(name, compression string, out io.Writer) error {
+	var c archive.Compression
+	switch compression {
+	case "", "none":
+		c = archive.Uncompressed
+	case "gzip":
+		c = archive.Gzip
+	case "zstd":
+		c = archive.Zstd
+	default:
+		return errdefs.InvalidParameter(errors.Errorf("unsupported compression %q", compression))
+	}
+
+	ref := "export-" + stringid.GenerateNonCryptoID()
+	v, path, err := daemon.mountVolumeWithRef(name, "", ref, nil, false)
+	if err != nil {
+		return err
+	}
+	defer daemon.unmountVolumeWithRef(v, ref)
+
+	arch, err := archive.TarWithOptions(path, &archive.TarOptions{
+		Compression: c,
+		UIDMaps:     daemon.idMappings.UIDs(),
+		GIDMaps:     daemon.idMappings.GIDs(),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error exporting volume %s", name)
+	}
+	defer arch.Close()
+
+	if _, err := io.Copy(out, arch); err != nil {
+		return errors.Wrapf(err, "error exporting volume %s", name)
+	}
+	daemon.LogVolumeEvent(v.Name(), "export", map[string]string{"driver": v.DriverName()})
+	return nil
+}
+
+// VolumeImport extracts the tar archive read from in into the volume with the
+// given name, created with the driver and labels if it does not exist. The
+// archive may be compressed. The files of the archive replace the files of
+// the volume with the same path, the other files are kept. The ownership of
+// the files is mapped to the remapped root of the daemon.
+func (daemon *Daemon) VolumeImport(name, driverName string, labels map[string]string, in io.Reader) (*types.Volume, error) {
+	if name == "" {
+		name = stringid.GenerateNonCryptoID()
+	}
+
+	ref := "import-" + stringid.GenerateNonCryptoID()
+	v, path, err := daemon.mountVolumeWithRef(name, driverName, ref, labels, true)
+	if err != nil {
+		return nil, err
+	}
+	defer daemon.unmountVolumeWithRef(v, ref)
+
+	if err := chrootarchive.Untar(in, path, &archive.TarOptions{
+		UIDMaps: daemon.idMappings.UIDs(),
+		GIDMaps: daemon.idMappings.GIDs(),
+	}); err != nil {
+		return nil, errdefs.InvalidParameter(errors.Wrapf(err, "error importing volume %s", name))
+	}
+
+	daemon.LogVolumeEvent(v.Name(), "import", map[string]string{"driver": v.DriverName()})
+	apiV := volumeToAPIType(v)
+	apiV.Mountpoint = v.Path()
+	return apiV, nil
+}
+
+// mountVolumeWithRef mounts the volume with the given name, referenced by ref
+// so that it is not removed until unmountVolumeWithRef is called. If create
+// is set, the volume is created if it does not exist.
+func (daemon *Daemon) mountVolumeWithRef(name, driverName, ref string, labels map[string]string, create bool) (volume.Volume, string, error) {
+	var (
+		v   volume.Volume
+		err error
+	)
+	if create {
+		v, err = daemon.volumes.CreateWithRef(name, driverName, ref, nil, labels)
+	} else {
+		if v, err = daemon.volumes.Get(name); err == nil {
+			v, err = daemon.volumes.GetWithRef(name, v.DriverName(), ref)
+		}
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	path, err := v.Mount(ref)
+	if err != nil {
+		daemon.volumes.Dereference(v, ref)
+		return nil, "", errors.Wrapf(err, "error mounting volume %s", name)
+	}
+	return v, path, nil
+}
+
+func (daemon *Daemon) unmountVolumeWithRef(v volume.Volume, ref string) {
+	if err := v.Unmount(ref); err != nil {
+		logrus.WithError(err).WithField("volume", v.Name()).Warn("error unmounting volume")
+	}
+	daemon.volumes.Dereference(v, ref)
+}
@@ -0,0 +1,82 @@
+// +build linux
+
+package daemon // import "github.com/ellcrys/docker/daemon"
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ellcrys/docker/daemon/events"
+	"github.com/ellcrys/docker/errdefs"
+	"github.com/ellcrys/docker/pkg/idtools"
+	"github.com/ellcrys/docker/pkg/reexec"
+	"github.com/ellcrys/docker/volume"
+	volumedrivers "github.com/ellcrys/docker/volume/drivers"
+	"github.com/ellcrys/docker/volume/local"
+	"github.com/ellcrys/docker/volume/store"
+	"github.com/gotestyourself/gotestyourself/assert"
+	is "github.com/gotestyourself/gotestyourself/assert/cmp"
+	"github.com/gotestyourself/gotestyourself/skip"
+)
+
+func init() {
+	reexec.Init()
+}
+
+func TestVolumeExportImport(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "skipping test that requires root")
+
+	tmp, err := ioutil.TempDir("", "docker-daemon-volume-archive")
+	assert.NilError(t, err)
+	defer os.RemoveAll(tmp)
+
+	drivers := volumedrivers.NewStore(nil)
+	volumes, err := store.New(tmp, drivers)
+	assert.NilError(t, err)
+	defer volumes.Shutdown()
+	volumesDriver, err := local.New(tmp, idtools.IDPair{UID: 0, GID: 0})
+	assert.NilError(t, err)
+	drivers.Register(volumesDriver, volumesDriver.Name())
+
+	daemon := &Daemon{
+		volumes:       volumes,
+		idMappings:    &idtools.IDMappings{},
+		EventsService: events.New(),
+	}
+
+	src, err := daemon.VolumeCreate("src", "local", nil, nil)
+	assert.NilError(t, err)
+	assert.NilError(t, os.MkdirAll(filepath.Join(src.Mountpoint, "dir"), 0755))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(src.Mountpoint, "dir", "file"), []byte("content"), 0600))
+
+	err = daemon.VolumeExport("src", "bzip2", ioutil.Discard)
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+
+	var buf bytes.Buffer
+	assert.NilError(t, daemon.VolumeExport("src", "gzip", &buf))
+	assert.Check(t, is.Len(volumes.Refs(mustGetVolume(t, volumes, "src")), 0))
+
+	dst, err := daemon.VolumeImport("dst", "", map[string]string{"imported": "true"}, &buf)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(dst.Labels["imported"], "true"))
+
+	content, err := ioutil.ReadFile(filepath.Join(dst.Mountpoint, "dir", "file"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(content), "content"))
+	fi, err := os.Stat(filepath.Join(dst.Mountpoint, "dir", "file"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(fi.Mode().Perm(), os.FileMode(0600)))
+	assert.Check(t, is.Len(volumes.Refs(mustGetVolume(t, volumes, "dst")), 0))
+
+	err = daemon.VolumeExport("missing", "", ioutil.Discard)
+	assert.Check(t, errdefs.IsNotFound(err), err)
+}
+
+func mustGetVolume(t *testing.T, volumes *store.VolumeStore, name string) volume.Volume {
+	v, err := volumes.Get(name)
+	assert.NilError(t, err)
+	return v
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/ellcrys/docker/errdefs"
 	"github.com/ellcrys/docker/volume"
 	volumemounts "github.com/ellcrys/docker/volume/mounts"
+	volumestore "github.com/ellcrys/docker/volume/store"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -44,6 +45,23 @@ func volumeToAPIType(v volume.Volume) *types.Volume {
 	return tv
 }
 
+// volumeUsageToAPIType converts the usage of a volume measured by the volume
+// store, referenced refs times, to the type used by the Engine API
+func volumeUsageToAPIType(u volumestore.Usage, refs int) *types.VolumeUsageData {
+	usage := &types.VolumeUsageData{
+		Size:     u.Size,
+		Limit:    u.Limit,
+		RefCount: int64(refs),
+	}
+	if u.Inodes > 0 {
+		usage.Inodes = u.Inodes
+	}
+	if !u.LastMounted.IsZero() {
+		usage.LastMounted = u.LastMounted.Format(time.RFC3339Nano)
+	}
+	return usage
+}
+
 // Len returns the number of mounts. Used in sorting.
 func (m mounts) Len() int {
 	return len(m)
@@ -9,11 +9,13 @@ import (
 
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/distribution/reference"
 	"github.com/ellcrys/docker/api/types"
 	"github.com/ellcrys/docker/distribution/metadata"
 	"github.com/ellcrys/docker/distribution/xfer"
 	"github.com/ellcrys/docker/image"
 	"github.com/ellcrys/docker/layer"
+	"github.com/ellcrys/docker/pkg/archive"
 	"github.com/ellcrys/docker/pkg/progress"
 	"github.com/ellcrys/docker/pkg/system"
 	refstore "github.com/ellcrys/docker/reference"
@@ -63,6 +65,25 @@ type ImagePullConfig struct {
 	// OS is the requested operating system of the image being pulled to ensure it can be validated
 	// when the host OS supports multiple image operating systems.
 	OS string
+	// PartialDownloads keeps the data of interrupted layer downloads, so
+	// that they can be resumed by later pulls. If nil, interrupted
+	// downloads are only resumed by the same pull.
+	PartialDownloads *xfer.PartialDownloads
+	// MaxDownloadChunks is the maximum number of parallel ranged requests
+	// a single large layer is downloaded with.
+	MaxDownloadChunks int
+	// SignatureVerifier verifies the manifests pulled against the
+	// signature policy of the daemon before their layers are downloaded.
+	// This value is optional.
+	SignatureVerifier SignatureVerifier
+}
+
+// SignatureVerifier verifies the images pulled against a signature policy.
+type SignatureVerifier interface {
+	// Verify returns an error if the image with the given manifest digest
+	// cannot be pulled from the repository of ref. manifestDigest is empty
+	// if the manifest is not addressed by digest, as with v1 registries.
+	Verify(ref reference.Named, manifestDigest digest.Digest) error
 }
 
 // ImagePushConfig stores push configuration.
@@ -79,6 +100,10 @@ type ImagePushConfig struct {
 	TrustKey libtrust.PrivateKey
 	// UploadManager dispatches uploads.
 	UploadManager *xfer.LayerUploadManager
+	// LayerCompression is the compression used for layer blobs that are
+	// uploaded. Only Gzip and Zstd are supported; the zero value
+	// (Uncompressed) is treated as Gzip.
+	LayerCompression archive.Compression
 }
 
 // ImageConfigStore handles storing and getting image configurations
@@ -107,6 +107,14 @@ func Pull(ctx context.Context, ref reference.Named, imagePullConfig *ImagePullCo
 			}
 		}
 
+		if imagePullConfig.SignatureVerifier != nil && endpoint.Version == registry.APIVersion1 {
+			// v1 manifests have no digest the signatures could be verified for
+			if err := imagePullConfig.SignatureVerifier.Verify(ref, ""); err != nil {
+				lastErr = err
+				continue
+			}
+		}
+
 		logrus.Debugf("Trying to pull %s from %s %s", reference.FamiliarName(repoInfo.Name), endpoint.URL, endpoint.Version)
 
 		puller, err := newPuller(endpoint, repoInfo, imagePullConfig)
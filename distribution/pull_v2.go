@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"net/url"
 	"os"
 	"runtime"
@@ -138,11 +137,16 @@ type v2LayerDescriptor struct {
 	repoInfo          *registry.RepositoryInfo
 	repo              distribution.Repository
 	V2MetadataService metadata.V2MetadataService
-	tmpFile           *os.File
-	verifier          digest.Verifier
+	download          *xfer.BlobDownload
 	src               distribution.Descriptor
+	partialDownloads  *xfer.PartialDownloads
+	maxDownloadChunks int
 }
 
+// minDownloadChunkSize is the smallest chunk a layer is split into when it
+// is downloaded in parallel chunks.
+const minDownloadChunkSize = 32 * 1024 * 1024
+
 func (ld *v2LayerDescriptor) Key() string {
 	return "v2:" + ld.digest.String()
 }
@@ -161,36 +165,23 @@ func (ld *v2LayerDescriptor) DiffID() (layer.DiffID, error) {
 func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progress.Output) (io.ReadCloser, int64, error) {
 	logrus.Debugf("pulling blob %q", ld.digest)
 
-	var (
-		err    error
-		offset int64
-	)
-
-	if ld.tmpFile == nil {
-		ld.tmpFile, err = createDownloadFile()
-		if err != nil {
-			return nil, 0, xfer.DoNotRetry{Err: err}
+	var err error
+	if ld.download == nil {
+		if ld.partialDownloads != nil {
+			ld.download, err = ld.partialDownloads.Open(ld.digest)
+		} else {
+			ld.download, err = xfer.NewTempBlobDownload(ld.digest)
 		}
-	} else {
-		offset, err = ld.tmpFile.Seek(0, os.SEEK_END)
 		if err != nil {
-			logrus.Debugf("error seeking to end of download file: %v", err)
-			offset = 0
-
-			ld.tmpFile.Close()
-			if err := os.Remove(ld.tmpFile.Name()); err != nil {
-				logrus.Errorf("Failed to remove temp file: %s", ld.tmpFile.Name())
-			}
-			ld.tmpFile, err = createDownloadFile()
-			if err != nil {
-				return nil, 0, xfer.DoNotRetry{Err: err}
-			}
-		} else if offset != 0 {
-			logrus.Debugf("attempting to resume download of %q from %d bytes", ld.digest, offset)
+			return nil, 0, xfer.DoNotRetry{Err: err}
 		}
 	}
 
-	tmpFile := ld.tmpFile
+	download := ld.download
+	offset := download.Offset()
+	if offset != 0 {
+		logrus.Debugf("attempting to resume download of %q from %d bytes", ld.digest, offset)
+	}
 
 	layerDownload, err := ld.open(ctx)
 	if err != nil {
@@ -198,70 +189,66 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 		return nil, 0, retryOnError(err)
 	}
 
-	if offset != 0 {
-		_, err := layerDownload.Seek(offset, os.SEEK_SET)
-		if err != nil {
-			if err := ld.truncateDownloadFile(); err != nil {
-				return nil, 0, xfer.DoNotRetry{Err: err}
-			}
-			return nil, 0, err
-		}
-	}
 	size, err := layerDownload.Seek(0, os.SEEK_END)
 	if err != nil {
 		// Seek failed, perhaps because there was no Content-Length
 		// header. This shouldn't fail the download, because we can
 		// still continue without a progress bar.
 		size = 0
-	} else {
-		if size != 0 && offset > size {
-			logrus.Debug("Partial download is larger than full blob. Starting over")
-			offset = 0
-			if err := ld.truncateDownloadFile(); err != nil {
-				return nil, 0, xfer.DoNotRetry{Err: err}
-			}
+	} else if size != 0 && offset > size {
+		logrus.Debug("Partial download is larger than full blob. Starting over")
+		offset = 0
+		if err := download.Reset(); err != nil {
+			return nil, 0, xfer.DoNotRetry{Err: err}
 		}
+	}
 
+	switch {
+	case size != 0 && offset == size:
+		// the blob was downloaded before the daemon restarted
+		layerDownload.Close()
+	case size != 0 && ld.maxDownloadChunks > 1 && size-offset >= 2*minDownloadChunkSize:
+		layerDownload.Close()
+		chunks := ld.maxDownloadChunks
+		if n := (size - offset) / minDownloadChunkSize; n < int64(chunks) {
+			chunks = int(n)
+		}
+		logrus.Debugf("downloading %q in %d chunks", ld.digest, chunks)
+		if err := download.DownloadChunks(ctx, size, chunks, ld.openAt, progressOutput, ld.ID()); err != nil {
+			return nil, 0, ld.downloadError(err)
+		}
+	default:
 		// Restore the seek offset either at the beginning of the
 		// stream, or just after the last byte we have from previous
 		// attempts.
-		_, err = layerDownload.Seek(offset, os.SEEK_SET)
-		if err != nil {
-			return nil, 0, err
-		}
-	}
-
-	reader := progress.NewProgressReader(ioutils.NewCancelReadCloser(ctx, layerDownload), progressOutput, size-offset, ld.ID(), "Downloading")
-	defer reader.Close()
-
-	if ld.verifier == nil {
-		ld.verifier = ld.digest.Verifier()
-	}
-
-	_, err = io.Copy(tmpFile, io.TeeReader(reader, ld.verifier))
-	if err != nil {
-		if err == transport.ErrWrongCodeForByteRange {
-			if err := ld.truncateDownloadFile(); err != nil {
+		if _, err := layerDownload.Seek(offset, os.SEEK_SET); err != nil {
+			layerDownload.Close()
+			if err := download.Reset(); err != nil {
 				return nil, 0, xfer.DoNotRetry{Err: err}
 			}
 			return nil, 0, err
 		}
-		return nil, 0, retryOnError(err)
+
+		reader := progress.NewProgressReader(ioutils.NewCancelReadCloser(ctx, layerDownload), progressOutput, size-offset, ld.ID(), "Downloading")
+		_, err = io.Copy(download, reader)
+		reader.Close()
+		if err != nil {
+			return nil, 0, ld.downloadError(err)
+		}
 	}
 
 	progress.Update(progressOutput, ld.ID(), "Verifying Checksum")
 
-	if !ld.verifier.Verified() {
+	if !download.Verified() {
 		err = fmt.Errorf("filesystem layer verification failed for digest %s", ld.digest)
 		logrus.Error(err)
 
+		if err := download.Reset(); err != nil {
+			return nil, 0, xfer.DoNotRetry{Err: err}
+		}
 		// Allow a retry if this digest verification error happened
 		// after a resumed download.
 		if offset != 0 {
-			if err := ld.truncateDownloadFile(); err != nil {
-				return nil, 0, xfer.DoNotRetry{Err: err}
-			}
-
 			return nil, 0, err
 		}
 		return nil, 0, xfer.DoNotRetry{Err: err}
@@ -269,62 +256,62 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 
 	progress.Update(progressOutput, ld.ID(), "Download complete")
 
-	logrus.Debugf("Downloaded %s to tempfile %s", ld.ID(), tmpFile.Name())
+	logrus.Debugf("Downloaded %s to file %s", ld.ID(), download.Name())
+
+	// hand off the download to the download manager, so it will only be
+	// closed once
+	ld.download = nil
 
-	_, err = tmpFile.Seek(0, os.SEEK_SET)
+	reader, err := download.Reader()
 	if err != nil {
-		tmpFile.Close()
-		if err := os.Remove(tmpFile.Name()); err != nil {
-			logrus.Errorf("Failed to remove temp file: %s", tmpFile.Name())
-		}
-		ld.tmpFile = nil
-		ld.verifier = nil
+		download.Remove()
 		return nil, 0, xfer.DoNotRetry{Err: err}
 	}
-
-	// hand off the temporary file to the download manager, so it will only
-	// be closed once
-	ld.tmpFile = nil
-
-	return ioutils.NewReadCloserWrapper(tmpFile, func() error {
-		tmpFile.Close()
-		err := os.RemoveAll(tmpFile.Name())
-		if err != nil {
-			logrus.Errorf("Failed to remove temp file: %s", tmpFile.Name())
-		}
-		return err
-	}), size, nil
+	return reader, size, nil
 }
 
-func (ld *v2LayerDescriptor) Close() {
-	if ld.tmpFile != nil {
-		ld.tmpFile.Close()
-		if err := os.RemoveAll(ld.tmpFile.Name()); err != nil {
-			logrus.Errorf("Failed to remove temp file: %s", ld.tmpFile.Name())
-		}
+// openAt returns a reader of the layer starting at offset.
+func (ld *v2LayerDescriptor) openAt(ctx context.Context, offset int64) (io.ReadCloser, error) {
+	rsc, err := ld.open(ctx)
+	if err != nil {
+		return nil, err
 	}
+	if _, err := rsc.Seek(offset, os.SEEK_SET); err != nil {
+		rsc.Close()
+		return nil, err
+	}
+	return rsc, nil
 }
 
-func (ld *v2LayerDescriptor) truncateDownloadFile() error {
-	// Need a new hash context since we will be redoing the download
-	ld.verifier = nil
-
-	if _, err := ld.tmpFile.Seek(0, os.SEEK_SET); err != nil {
-		logrus.Errorf("error seeking to beginning of download file: %v", err)
+// downloadError returns the error to report for a failed transfer of the
+// layer data. The data downloaded so far is kept, to be resumed from, unless
+// the registry cannot serve byte ranges.
+func (ld *v2LayerDescriptor) downloadError(err error) error {
+	if err == transport.ErrWrongCodeForByteRange {
+		if err := ld.download.Reset(); err != nil {
+			return xfer.DoNotRetry{Err: err}
+		}
 		return err
 	}
-
-	if err := ld.tmpFile.Truncate(0); err != nil {
-		logrus.Errorf("error truncating download file: %v", err)
-		return err
+	if err := ld.download.Checkpoint(); err != nil {
+		logrus.WithError(err).Warnf("failed to save the state of the download of %s", ld.digest)
 	}
+	return retryOnError(err)
+}
 
-	return nil
+func (ld *v2LayerDescriptor) Close() {
+	if ld.download != nil {
+		ld.download.Close()
+	}
 }
 
 func (ld *v2LayerDescriptor) Registered(diffID layer.DiffID) {
 	// Cache mapping from this layer's DiffID to the blobsum
-	ld.V2MetadataService.Add(diffID, metadata.V2Metadata{Digest: ld.digest, SourceRepository: ld.repoInfo.Name.Name()})
+	meta := metadata.V2Metadata{Digest: ld.digest, SourceRepository: ld.repoInfo.Name.Name()}
+	if ld.src.MediaType == MediaTypeZstdLayer {
+		meta.MediaType = MediaTypeZstdLayer
+	}
+	ld.V2MetadataService.Add(diffID, meta)
 }
 
 func (p *v2Puller) pullV2Tag(ctx context.Context, ref reference.Named, os string) (tagUpdated bool, err error) {
@@ -381,6 +368,12 @@ func (p *v2Puller) pullV2Tag(ctx context.Context, ref reference.Named, os string
 	logrus.Debugf("Pulling ref from V2 registry: %s", reference.FamiliarString(ref))
 	progress.Message(p.config.ProgressOutput, tagOrDigest, "Pulling from "+reference.FamiliarName(p.repo.Named()))
 
+	if p.config.SignatureVerifier != nil {
+		if err := p.verifySignature(ref, manifest); err != nil {
+			return false, err
+		}
+	}
+
 	var (
 		id             digest.Digest
 		manifestDigest digest.Digest
@@ -484,6 +477,8 @@ func (p *v2Puller) pullSchema1(ctx context.Context, ref reference.Reference, unv
 			repoInfo:          p.repoInfo,
 			repo:              p.repo,
 			V2MetadataService: p.V2MetadataService,
+			partialDownloads:  p.config.PartialDownloads,
+			maxDownloadChunks: p.config.MaxDownloadChunks,
 		}
 
 		descriptors = append(descriptors, layerDescriptor)
@@ -560,6 +555,8 @@ func (p *v2Puller) pullSchema2(ctx context.Context, ref reference.Named, mfst *s
 			repoInfo:          p.repoInfo,
 			V2MetadataService: p.V2MetadataService,
 			src:               d,
+			partialDownloads:  p.config.PartialDownloads,
+			maxDownloadChunks: p.config.MaxDownloadChunks,
 		}
 
 		descriptors = append(descriptors, layerDescriptor)
@@ -826,6 +823,24 @@ func schema2ManifestDigest(ref reference.Named, mfst distribution.Manifest) (dig
 	return digest.FromBytes(canonical), nil
 }
 
+// verifySignature verifies the manifest against the signature policy, before
+// any layer is downloaded.
+func (p *v2Puller) verifySignature(ref reference.Named, manifest distribution.Manifest) error {
+	var (
+		manifestDigest digest.Digest
+		err            error
+	)
+	if v, ok := manifest.(*schema1.SignedManifest); ok {
+		manifestDigest = digest.FromBytes(v.Canonical)
+	} else {
+		manifestDigest, err = schema2ManifestDigest(ref, manifest)
+		if err != nil {
+			return err
+		}
+	}
+	return p.config.SignatureVerifier.Verify(ref, manifestDigest)
+}
+
 // allowV1Fallback checks if the error is a possible reason to fallback to v1
 // (even if confirmedV2 has been set already), and if so, wraps the error in
 // a fallbackError with confirmedV2 set to false. Otherwise, it returns the
@@ -935,7 +950,3 @@ func fixManifestLayers(m *schema1.Manifest) error {
 
 	return nil
 }
-
-func createDownloadFile() (*os.File, error) {
-	return ioutil.TempFile("", "GetImageBlob")
-}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/docker/distribution/reference"
 	"github.com/ellcrys/docker/distribution/metadata"
+	"github.com/ellcrys/docker/pkg/archive"
 	"github.com/ellcrys/docker/pkg/progress"
 	"github.com/ellcrys/docker/registry"
 	"github.com/sirupsen/logrus"
@@ -158,13 +159,26 @@ func Push(ctx context.Context, ref reference.Named, imagePushConfig *ImagePushCo
 // is finished. This allows the caller to make sure the goroutine finishes
 // before it releases any resources connected with the reader that was
 // passed in.
-func compress(in io.Reader) (io.ReadCloser, chan struct{}) {
+func compress(in io.Reader, compression archive.Compression) (io.ReadCloser, chan struct{}, error) {
 	compressionDone := make(chan struct{})
 
 	pipeReader, pipeWriter := io.Pipe()
 	// Use a bufio.Writer to avoid excessive chunking in HTTP request.
 	bufWriter := bufio.NewWriterSize(pipeWriter, compressionBufSize)
-	compressor := gzip.NewWriter(bufWriter)
+
+	var compressor io.WriteCloser
+	switch compression {
+	case archive.Gzip:
+		compressor = gzip.NewWriter(bufWriter)
+	case archive.Zstd:
+		var err error
+		compressor, err = archive.CompressStream(bufWriter, archive.Zstd)
+		if err != nil {
+			return nil, nil, err
+		}
+	default:
+		return nil, nil, fmt.Errorf("unsupported layer compression %s", compression.Extension())
+	}
 
 	go func() {
 		_, err := io.Copy(compressor, in)
@@ -182,5 +196,5 @@ func compress(in io.Reader) (io.ReadCloser, chan struct{}) {
 		close(compressionDone)
 	}()
 
-	return pipeReader, compressionDone
+	return pipeReader, compressionDone, nil
 }
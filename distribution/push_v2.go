@@ -20,6 +20,7 @@ import (
 	"github.com/ellcrys/docker/distribution/metadata"
 	"github.com/ellcrys/docker/distribution/xfer"
 	"github.com/ellcrys/docker/layer"
+	"github.com/ellcrys/docker/pkg/archive"
 	"github.com/ellcrys/docker/pkg/ioutils"
 	"github.com/ellcrys/docker/pkg/progress"
 	"github.com/ellcrys/docker/pkg/stringid"
@@ -143,6 +144,11 @@ func (p *v2Pusher) pushV2Tag(ctx context.Context, ref reference.NamedTagged, id
 
 	var descriptors []xfer.UploadDescriptor
 
+	layerCompression := p.config.LayerCompression
+	if layerCompression == archive.Uncompressed {
+		layerCompression = archive.Gzip
+	}
+
 	descriptorTemplate := v2PushDescriptor{
 		v2MetadataService: p.v2MetadataService,
 		hmacKey:           hmacKey,
@@ -151,6 +157,7 @@ func (p *v2Pusher) pushV2Tag(ctx context.Context, ref reference.NamedTagged, id
 		endpoint:          p.endpoint,
 		repo:              p.repo,
 		pushState:         &p.pushState,
+		layerCompression:  layerCompression,
 	}
 
 	// Loop bounds condition is to avoid pushing the base layer on Windows.
@@ -251,6 +258,7 @@ type v2PushDescriptor struct {
 	repo              distribution.Repository
 	pushState         *pushState
 	remoteDescriptor  distribution.Descriptor
+	layerCompression  archive.Compression
 	// a set of digests whose presence has been checked in a target repository
 	checkedDigests map[digest.Digest]struct{}
 }
@@ -294,6 +302,8 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 
 	// Do we have any metadata associated with this layer's DiffID?
 	v2Metadata, err := pd.v2MetadataService.GetMetadata(diffID)
+	// Only blobs compressed the way we were asked to push are reusable
+	v2Metadata = filterV2MetadataByMediaType(v2Metadata, pd.layerMediaType())
 	if err == nil {
 		// check for blob existence in the target repository
 		descriptor, exists, err := pd.layerAlreadyExists(ctx, progressOutput, diffID, true, 1, v2Metadata)
@@ -348,7 +358,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 		case distribution.ErrBlobMounted:
 			progress.Updatef(progressOutput, pd.ID(), "Mounted from %s", err.From.Name())
 
-			err.Descriptor.MediaType = schema2.MediaTypeLayer
+			err.Descriptor.MediaType = pd.layerMediaType()
 
 			pd.pushState.Lock()
 			pd.pushState.confirmedV2 = true
@@ -359,6 +369,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 			if err := pd.v2MetadataService.TagAndAdd(diffID, pd.hmacKey, metadata.V2Metadata{
 				Digest:           err.Descriptor.Digest,
 				SourceRepository: pd.repoInfo.Name(),
+				MediaType:        v2MetadataMediaType(pd.layerMediaType()),
 			}); err != nil {
 				return distribution.Descriptor{}, xfer.DoNotRetry{Err: err}
 			}
@@ -420,6 +431,15 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 	return pd.uploadUsingSession(ctx, progressOutput, diffID, layerUpload)
 }
 
+// layerMediaType returns the media type of the blob produced when the layer
+// is compressed for upload.
+func (pd *v2PushDescriptor) layerMediaType() string {
+	if pd.layerCompression == archive.Zstd {
+		return MediaTypeZstdLayer
+	}
+	return schema2.MediaTypeLayer
+}
+
 func (pd *v2PushDescriptor) SetRemoteDescriptor(descriptor distribution.Descriptor) {
 	pd.remoteDescriptor = descriptor
 }
@@ -447,7 +467,11 @@ func (pd *v2PushDescriptor) uploadUsingSession(
 
 	switch m := pd.layer.MediaType(); m {
 	case schema2.MediaTypeUncompressedLayer:
-		compressedReader, compressionDone := compress(reader)
+		compressedReader, compressionDone, err := compress(reader, pd.layerCompression)
+		if err != nil {
+			reader.Close()
+			return distribution.Descriptor{}, xfer.DoNotRetry{Err: err}
+		}
 		defer func(closer io.Closer) {
 			closer.Close()
 			<-compressionDone
@@ -480,13 +504,14 @@ func (pd *v2PushDescriptor) uploadUsingSession(
 	if err := pd.v2MetadataService.TagAndAdd(diffID, pd.hmacKey, metadata.V2Metadata{
 		Digest:           pushDigest,
 		SourceRepository: pd.repoInfo.Name(),
+		MediaType:        v2MetadataMediaType(pd.layerMediaType()),
 	}); err != nil {
 		return distribution.Descriptor{}, xfer.DoNotRetry{Err: err}
 	}
 
 	desc := distribution.Descriptor{
 		Digest:    pushDigest,
-		MediaType: schema2.MediaTypeLayer,
+		MediaType: pd.layerMediaType(),
 		Size:      nn,
 	}
 
@@ -555,11 +580,12 @@ attempts:
 				if err := pd.v2MetadataService.TagAndAdd(diffID, pd.hmacKey, metadata.V2Metadata{
 					Digest:           desc.Digest,
 					SourceRepository: pd.repoInfo.Name(),
+					MediaType:        v2MetadataMediaType(pd.layerMediaType()),
 				}); err != nil {
 					return distribution.Descriptor{}, false, xfer.DoNotRetry{Err: err}
 				}
 			}
-			desc.MediaType = schema2.MediaTypeLayer
+			desc.MediaType = pd.layerMediaType()
 			exists = true
 			break attempts
 		case distribution.ErrBlobUnknown:
@@ -610,6 +636,29 @@ func getMaxMountAndExistenceCheckAttempts(layer PushLayer) (maxMountAttempts, ma
 	}
 }
 
+// v2MetadataMediaType returns the value to record as the media type of a
+// blob in v2 metadata. Gzip compressed layers are recorded with an empty
+// media type to stay compatible with metadata written by older daemons.
+func v2MetadataMediaType(mediaType string) string {
+	if mediaType == schema2.MediaTypeLayer {
+		return ""
+	}
+	return mediaType
+}
+
+// filterV2MetadataByMediaType returns the v2 metadata items describing blobs
+// of the given media type.
+func filterV2MetadataByMediaType(v2Metadata []metadata.V2Metadata, mediaType string) []metadata.V2Metadata {
+	mediaType = v2MetadataMediaType(mediaType)
+	filtered := v2Metadata[:0:0]
+	for _, meta := range v2Metadata {
+		if meta.MediaType == mediaType {
+			filtered = append(filtered, meta)
+		}
+	}
+	return filtered
+}
+
 // getRepositoryMountCandidates returns an array of v2 metadata items belonging to the given registry. The
 // array is sorted from youngest to oldest. If requireRegistryMatch is true, the resulting array will contain
 // only metadata entries having registry part of SourceRepository matching the part of repoInfo.
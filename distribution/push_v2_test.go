@@ -149,6 +149,22 @@ func TestGetRepositoryMountCandidates(t *testing.T) {
 	}
 }
 
+func TestFilterV2MetadataByMediaType(t *testing.T) {
+	gzipMeta := metadata.V2Metadata{Digest: digest.Digest("sha256:1"), SourceRepository: "docker.io/library/busybox"}
+	zstdMeta := metadata.V2Metadata{Digest: digest.Digest("sha256:2"), SourceRepository: "docker.io/library/busybox", MediaType: MediaTypeZstdLayer}
+	v2Metadata := []metadata.V2Metadata{gzipMeta, zstdMeta}
+
+	filtered := filterV2MetadataByMediaType(v2Metadata, schema2.MediaTypeLayer)
+	if !reflect.DeepEqual(filtered, []metadata.V2Metadata{gzipMeta}) {
+		t.Errorf("unexpected metadata for gzip layers: %v", filtered)
+	}
+
+	filtered = filterV2MetadataByMediaType(v2Metadata, MediaTypeZstdLayer)
+	if !reflect.DeepEqual(filtered, []metadata.V2Metadata{zstdMeta}) {
+		t.Errorf("unexpected metadata for zstd layers: %v", filtered)
+	}
+}
+
 func TestLayerAlreadyExists(t *testing.T) {
 	for _, tc := range []struct {
 		name                   string
@@ -19,6 +19,9 @@ import (
 	"github.com/docker/go-connections/sockets"
 )
 
+// MediaTypeZstdLayer is the media type used for zstd compressed layers.
+const MediaTypeZstdLayer = "application/vnd.oci.image.layer.v1.tar+zstd"
+
 // ImageTypes represents the schema2 config types for images
 var ImageTypes = []string{
 	schema2.MediaTypeImageConfig,
@@ -0,0 +1,145 @@
+package xfer // import "github.com/ellcrys/docker/distribution/xfer"
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ellcrys/docker/pkg/ioutils"
+	"github.com/ellcrys/docker/pkg/progress"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+// RangeOpener returns a reader of the blob data starting at offset.
+type RangeOpener func(ctx context.Context, offset int64) (io.ReadCloser, error)
+
+// DownloadChunks downloads the rest of the blob, up to size, in chunks
+// fetched in parallel. The chunks are digested once all of them are
+// downloaded. If a chunk fails, the data downloaded contiguously from the
+// offset is kept, so that the download can be resumed from there.
+func (d *BlobDownload) DownloadChunks(ctx context.Context, size int64, chunks int, open RangeOpener, progressOutput progress.Output, id string) error {
+	remaining := size - d.offset
+	if remaining <= 0 {
+		return nil
+	}
+	chunkSize := (remaining + int64(chunks) - 1) / int64(chunks)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		lengths = make([]int64, chunks)
+		written = make([]int64, chunks)
+		errs    = make([]error, chunks)
+		bar     = newChunksProgress(progressOutput, id, remaining)
+	)
+	for i := 0; i < chunks; i++ {
+		start := d.offset + int64(i)*chunkSize
+		lengths[i] = chunkSize
+		if start+chunkSize > size {
+			lengths[i] = size - start
+		}
+		if lengths[i] <= 0 {
+			lengths[i] = 0
+			continue
+		}
+		wg.Add(1)
+		go func(i int, start int64) {
+			defer wg.Done()
+			if errs[i] = d.downloadChunk(ctx, open, start, lengths[i], &written[i], bar); errs[i] != nil {
+				cancel()
+			}
+		}(i, start)
+	}
+	wg.Wait()
+
+	var contiguous int64
+	for i := range lengths {
+		contiguous += written[i]
+		if written[i] < lengths[i] {
+			break
+		}
+	}
+	if err := d.advance(contiguous); err != nil {
+		return err
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			// the data after the first gap cannot be digested yet
+			if err := d.file.Truncate(d.offset); err != nil {
+				return err
+			}
+			return err
+		}
+	}
+	if err := d.Checkpoint(); err != nil {
+		logrus.WithError(err).Warnf("failed to save the state of the download of %s", d.digest)
+	}
+	return nil
+}
+
+func (d *BlobDownload) downloadChunk(ctx context.Context, open RangeOpener, start, length int64, written *int64, bar *chunksProgress) error {
+	rc, err := open(ctx, start)
+	if err != nil {
+		return err
+	}
+	rc = ioutils.NewCancelReadCloser(ctx, rc)
+	defer rc.Close()
+
+	r := io.LimitReader(rc, length)
+	buf := make([]byte, 32*1024)
+	for *written < length {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if _, err := d.file.WriteAt(buf[:n], start+*written); err != nil {
+				return err
+			}
+			*written += int64(n)
+			bar.add(int64(n))
+		}
+		if err == io.EOF {
+			if *written < length {
+				return io.ErrUnexpectedEOF
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chunksProgress reports the progress of the chunks of a download as a
+// single progress bar.
+type chunksProgress struct {
+	out         progress.Output
+	id          string
+	total       int64
+	rateLimiter *rate.Limiter
+
+	mu      sync.Mutex
+	current int64
+}
+
+func newChunksProgress(out progress.Output, id string, total int64) *chunksProgress {
+	return &chunksProgress{
+		out:         out,
+		id:          id,
+		total:       total,
+		rateLimiter: rate.NewLimiter(rate.Every(100*time.Millisecond), 1),
+	}
+}
+
+func (p *chunksProgress) add(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current += n
+	if p.current == p.total || p.rateLimiter.Allow() {
+		p.out.WriteProgress(progress.Progress{ID: p.id, Action: "Downloading", Current: p.current, Total: p.total})
+	}
+}
@@ -0,0 +1,324 @@
+package xfer // import "github.com/ellcrys/docker/distribution/xfer"
+
+import (
+	"encoding"
+	"encoding/json"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ellcrys/docker/pkg/ioutils"
+	"github.com/opencontainers/go-digest"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// checkpointInterval is the amount of data written to a blob download
+	// between two saves of its digest state.
+	checkpointInterval = 16 * 1024 * 1024
+
+	// partialDownloadMaxAge is the time after which a partial download
+	// which was not resumed is removed.
+	partialDownloadMaxAge = 7 * 24 * time.Hour
+
+	stateFileSuffix = ".state"
+)
+
+// PartialDownloads keeps the blobs being downloaded in a directory, along
+// with the state of their digest, so that an interrupted download can be
+// resumed from where it stopped, even after a daemon restart.
+type PartialDownloads struct {
+	root string
+
+	mu     sync.Mutex
+	active map[digest.Digest]bool
+}
+
+// NewPartialDownloads returns a PartialDownloads keeping the blobs in root.
+// Partial downloads which were not resumed for a week are removed.
+func NewPartialDownloads(root string) (*PartialDownloads, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, err
+	}
+	pd := &PartialDownloads{
+		root:   root,
+		active: make(map[digest.Digest]bool),
+	}
+	pd.removeExpired()
+	return pd, nil
+}
+
+func (pd *PartialDownloads) removeExpired() {
+	fis, err := ioutil.ReadDir(pd.root)
+	if err != nil {
+		logrus.WithError(err).Warn("failed to list partial downloads")
+		return
+	}
+	for _, fi := range fis {
+		if fi.IsDir() || strings.HasSuffix(fi.Name(), stateFileSuffix) || time.Since(fi.ModTime()) < partialDownloadMaxAge {
+			continue
+		}
+		p := filepath.Join(pd.root, fi.Name())
+		logrus.Debugf("removing expired partial download %s", p)
+		os.Remove(p)
+		os.Remove(p + stateFileSuffix)
+	}
+}
+
+// Open returns the download of the blob, resumed from the data kept by
+// earlier attempts if there is any. If the blob is already being downloaded,
+// the download returned is not kept once closed.
+func (pd *PartialDownloads) Open(dgst digest.Digest) (*BlobDownload, error) {
+	if err := dgst.Validate(); err != nil {
+		return nil, err
+	}
+
+	pd.mu.Lock()
+	busy := pd.active[dgst]
+	pd.active[dgst] = true
+	pd.mu.Unlock()
+	if busy {
+		return NewTempBlobDownload(dgst)
+	}
+
+	release := func() {
+		pd.mu.Lock()
+		delete(pd.active, dgst)
+		pd.mu.Unlock()
+	}
+	p := filepath.Join(pd.root, dgst.Algorithm().String()+"-"+dgst.Hex())
+	f, err := os.OpenFile(p, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	d := &BlobDownload{
+		digest:    dgst,
+		file:      f,
+		statePath: p + stateFileSuffix,
+		release:   release,
+	}
+	if err := d.restore(); err != nil {
+		logrus.WithError(err).Debugf("cannot resume the download of %s, starting over", dgst)
+		if err := d.Reset(); err != nil {
+			d.Close()
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// BlobDownload is a blob being downloaded to a file, and digested as it is
+// written.
+type BlobDownload struct {
+	digest digest.Digest
+	file   *os.File
+	// statePath is where the digest state is saved, or empty if the
+	// download is removed on Close.
+	statePath    string
+	release      func()
+	hash         hash.Hash
+	offset       int64
+	checkpointed int64
+	closeOnce    sync.Once
+	releaseOnce  sync.Once
+}
+
+// blobDownloadState is the saved state of a BlobDownload. The data written
+// after Offset is not part of the Hash state, and is discarded.
+type blobDownloadState struct {
+	Offset int64
+	Hash   []byte
+}
+
+// NewTempBlobDownload returns a download of the blob to a temporary file,
+// which is removed on Close.
+func NewTempBlobDownload(dgst digest.Digest) (*BlobDownload, error) {
+	f, err := ioutil.TempFile("", "GetImageBlob")
+	if err != nil {
+		return nil, err
+	}
+	return &BlobDownload{
+		digest: dgst,
+		file:   f,
+		hash:   dgst.Algorithm().Hash(),
+	}, nil
+}
+
+// restore sets the offset and digest state of the download from the saved
+// state, or from the data kept if the state cannot be used.
+func (d *BlobDownload) restore() error {
+	d.hash = d.digest.Algorithm().Hash()
+	fi, err := d.file.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.Size() == 0 {
+		return nil
+	}
+
+	var state blobDownloadState
+	b, err := ioutil.ReadFile(d.statePath)
+	if err == nil {
+		err = json.Unmarshal(b, &state)
+	}
+	if u, ok := d.hash.(encoding.BinaryUnmarshaler); ok && err == nil && state.Offset <= fi.Size() {
+		if err := u.UnmarshalBinary(state.Hash); err == nil {
+			if err := d.file.Truncate(state.Offset); err != nil {
+				return err
+			}
+			d.offset = state.Offset
+			d.checkpointed = state.Offset
+			_, err = d.file.Seek(d.offset, io.SeekStart)
+			return err
+		}
+		d.hash = d.digest.Algorithm().Hash()
+	}
+
+	// no usable digest state, digest the data kept again
+	if _, err := d.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	n, err := io.Copy(d.hash, d.file)
+	if err != nil {
+		return err
+	}
+	d.offset = n
+	return nil
+}
+
+// Offset returns the size of the data downloaded so far.
+func (d *BlobDownload) Offset() int64 {
+	return d.offset
+}
+
+// Name returns the name of the file the blob is downloaded to.
+func (d *BlobDownload) Name() string {
+	return d.file.Name()
+}
+
+// Write appends p to the data downloaded.
+func (d *BlobDownload) Write(p []byte) (int, error) {
+	n, err := d.file.Write(p)
+	d.hash.Write(p[:n])
+	d.offset += int64(n)
+	if err == nil && d.offset-d.checkpointed >= checkpointInterval {
+		if err := d.Checkpoint(); err != nil {
+			logrus.WithError(err).Warnf("failed to save the state of the download of %s", d.digest)
+		}
+	}
+	return n, err
+}
+
+// advance digests n bytes written past the offset with WriteAt, and moves
+// the offset after them.
+func (d *BlobDownload) advance(n int64) error {
+	if _, err := io.Copy(d.hash, io.NewSectionReader(d.file, d.offset, n)); err != nil {
+		return err
+	}
+	d.offset += n
+	_, err := d.file.Seek(d.offset, io.SeekStart)
+	return err
+}
+
+// Checkpoint saves the digest state of the data downloaded so far, so that
+// the download can be resumed after a daemon restart. It does nothing for
+// temporary downloads.
+func (d *BlobDownload) Checkpoint() error {
+	if d.statePath == "" || d.offset == d.checkpointed {
+		return nil
+	}
+	m, ok := d.hash.(encoding.BinaryMarshaler)
+	if !ok {
+		// the data kept is digested again when the download is resumed
+		return nil
+	}
+	h, err := m.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := d.file.Sync(); err != nil {
+		return err
+	}
+	b, err := json.Marshal(blobDownloadState{Offset: d.offset, Hash: h})
+	if err != nil {
+		return err
+	}
+	if err := ioutils.AtomicWriteFile(d.statePath, b, 0600); err != nil {
+		return err
+	}
+	d.checkpointed = d.offset
+	return nil
+}
+
+// Reset discards the data downloaded so far.
+func (d *BlobDownload) Reset() error {
+	d.hash = d.digest.Algorithm().Hash()
+	d.offset = 0
+	d.checkpointed = 0
+	if d.statePath != "" {
+		if err := os.Remove(d.statePath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if _, err := d.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return d.file.Truncate(0)
+}
+
+// Verified returns whether the data downloaded matches the blob digest.
+func (d *BlobDownload) Verified() bool {
+	return digest.NewDigest(d.digest.Algorithm(), d.hash) == d.digest
+}
+
+// Reader returns a reader of the data downloaded. The download is removed
+// once the reader is closed, and must not be used anymore.
+func (d *BlobDownload) Reader() (io.ReadCloser, error) {
+	if _, err := d.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return ioutils.NewReadCloserWrapper(d.file, d.Remove), nil
+}
+
+// Close closes the download. Partial downloads are kept to be resumed later,
+// temporary downloads are removed.
+func (d *BlobDownload) Close() error {
+	if d.statePath == "" {
+		return d.Remove()
+	}
+	if err := d.Checkpoint(); err != nil {
+		logrus.WithError(err).Warnf("failed to save the state of the download of %s", d.digest)
+	}
+	err := d.close()
+	d.releaseOnce.Do(d.release)
+	return err
+}
+
+// Remove closes the download and removes its data.
+func (d *BlobDownload) Remove() error {
+	err := d.close()
+	if err := os.Remove(d.file.Name()); err != nil && !os.IsNotExist(err) {
+		logrus.Errorf("Failed to remove download file: %s", d.file.Name())
+		return err
+	}
+	if d.statePath != "" {
+		os.Remove(d.statePath)
+		// the blob may be downloaded again only once its data is removed
+		d.releaseOnce.Do(d.release)
+	}
+	return err
+}
+
+func (d *BlobDownload) close() error {
+	var err error
+	d.closeOnce.Do(func() {
+		err = d.file.Close()
+	})
+	return err
+}
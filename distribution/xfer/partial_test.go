@@ -0,0 +1,199 @@
+package xfer // import "github.com/ellcrys/docker/distribution/xfer"
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ellcrys/docker/pkg/progress"
+	"github.com/opencontainers/go-digest"
+)
+
+func TestPartialDownloadResume(t *testing.T) {
+	root, err := ioutil.TempDir("", "partial-downloads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+	dgst := digest.FromBytes(data)
+
+	pd, err := NewPartialDownloads(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := pd.Open(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Write(data[:4000]); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a new PartialDownloads, as after a daemon restart
+	pd, err = NewPartialDownloads(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err = pd.Open(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Offset() != 4000 {
+		t.Fatalf("expected the download to resume from 4000, got %d", d.Offset())
+	}
+	if _, err := d.Write(data[4000:]); err != nil {
+		t.Fatal(err)
+	}
+	if !d.Verified() {
+		t.Fatal("expected the resumed download to be verified")
+	}
+
+	rc, err := d.Reader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, data) {
+		t.Fatal("unexpected data downloaded")
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if fis, _ := ioutil.ReadDir(root); len(fis) != 0 {
+		t.Fatalf("expected the download to be removed, found %d files", len(fis))
+	}
+}
+
+func TestPartialDownloadResumeWithoutState(t *testing.T) {
+	root, err := ioutil.TempDir("", "partial-downloads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	data := []byte("some layer data")
+	dgst := digest.FromBytes(data)
+	name := filepath.Join(root, dgst.Algorithm().String()+"-"+dgst.Hex())
+	if err := ioutil.WriteFile(name, data[:5], 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	pd, err := NewPartialDownloads(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := pd.Open(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Remove()
+	if d.Offset() != 5 {
+		t.Fatalf("expected the download to resume from 5, got %d", d.Offset())
+	}
+	if _, err := d.Write(data[5:]); err != nil {
+		t.Fatal(err)
+	}
+	if !d.Verified() {
+		t.Fatal("expected the resumed download to be verified")
+	}
+}
+
+func TestPartialDownloadBusy(t *testing.T) {
+	root, err := ioutil.TempDir("", "partial-downloads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	dgst := digest.FromBytes([]byte("data"))
+	pd, err := NewPartialDownloads(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d1, err := pd.Open(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d1.Remove()
+	d2, err := pd.Open(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d2.Remove()
+	if d1.Name() == d2.Name() {
+		t.Fatal("expected a blob being downloaded to be downloaded again to another file")
+	}
+}
+
+func TestDownloadChunks(t *testing.T) {
+	data := bytes.Repeat([]byte("abcdefghij"), 1000)
+	dgst := digest.FromBytes(data)
+	open := func(ctx context.Context, offset int64) (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(data[offset:])), nil
+	}
+
+	d, err := NewTempBlobDownload(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Remove()
+	if _, err := d.Write(data[:1234]); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.DownloadChunks(context.Background(), int64(len(data)), 4, open, progress.DiscardOutput(), "id"); err != nil {
+		t.Fatal(err)
+	}
+	if d.Offset() != int64(len(data)) {
+		t.Fatalf("expected offset %d, got %d", len(data), d.Offset())
+	}
+	if !d.Verified() {
+		t.Fatal("expected the chunked download to be verified")
+	}
+}
+
+func TestDownloadChunksFailure(t *testing.T) {
+	data := bytes.Repeat([]byte("abcdefghij"), 1000)
+	dgst := digest.FromBytes(data)
+	failAt := int64(5000)
+	open := func(ctx context.Context, offset int64) (io.ReadCloser, error) {
+		if offset >= failAt {
+			return nil, errors.New("connection reset")
+		}
+		return ioutil.NopCloser(bytes.NewReader(data[offset:])), nil
+	}
+
+	d, err := NewTempBlobDownload(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Remove()
+	if err := d.DownloadChunks(context.Background(), int64(len(data)), 4, open, progress.DiscardOutput(), "id"); err == nil {
+		t.Fatal("expected the chunked download to fail")
+	}
+	// the first two chunks may have been interrupted when the last ones failed
+	if d.Offset() > failAt {
+		t.Fatalf("expected to keep at most %d bytes, got %d", failAt, d.Offset())
+	}
+
+	if err := d.DownloadChunks(context.Background(), int64(len(data)), 1, func(ctx context.Context, offset int64) (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(data[offset:])), nil
+	}, progress.DiscardOutput(), "id"); err != nil {
+		t.Fatal(err)
+	}
+	if !d.Verified() {
+		t.Fatal("expected the resumed download to be verified")
+	}
+}
@@ -212,7 +212,9 @@ func NewHistory(author, comment, createdBy string, isEmptyLayer bool) History {
 type Exporter interface {
 	Load(io.ReadCloser, io.Writer, bool) error
 	// TODO: Load(net.Context, io.ReadCloser, <- chan StatusMessage) error
-	Save([]string, io.Writer) error
+	// Save writes the images with the given names to the writer, as a tar
+	// archive of the given format.
+	Save(names []string, format string, outStream io.Writer) error
 }
 
 // NewFromJSON creates an Image configuration from json.
@@ -0,0 +1,248 @@
+// Package signature implements the signature policy enforced by the daemon on
+// the images it pulls and runs. The policy maps repository name patterns to
+// an action: allow, reject, or require a detached signature of the image
+// manifest, made by one of the public keys configured for the pattern.
+package signature // import "github.com/ellcrys/docker/image/signature"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	"github.com/ellcrys/docker/errdefs"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// Action is the action of a policy rule.
+type Action string
+
+const (
+	// ActionAllow allows the images of the matching repositories.
+	ActionAllow Action = "allow"
+	// ActionReject rejects the images of the matching repositories.
+	ActionReject Action = "reject"
+	// ActionRequireSignature only allows the images of the matching
+	// repositories with a valid signature made by one of the keys of the
+	// rule.
+	ActionRequireSignature Action = "require-signature"
+)
+
+// Requirement is the action applied to the images of a repository, and the
+// paths of the PEM encoded public keys trusted for require-signature.
+type Requirement struct {
+	Action Action   `json:"action"`
+	Keys   []string `json:"keys,omitempty"`
+}
+
+// Rule applies a requirement to the repositories matching Repository. The
+// pattern is either a fully qualified repository name such as
+// "docker.io/library/alpine", a prefix of the repositories under it such as
+// "registry.corp/*", or "*" for all the repositories.
+type Rule struct {
+	Repository string `json:"repository"`
+	Requirement
+}
+
+// policyFile is the format of the policy file.
+type policyFile struct {
+	Default Requirement `json:"default"`
+	Rules   []Rule      `json:"rules,omitempty"`
+}
+
+type requirement struct {
+	action Action
+	keys   []publicKey
+}
+
+type rule struct {
+	pattern string
+	prefix  bool
+	requirement
+}
+
+func (r rule) match(name string) bool {
+	switch {
+	case r.pattern == "*":
+		return true
+	case r.prefix:
+		return strings.HasPrefix(name, r.pattern+"/")
+	default:
+		return name == r.pattern
+	}
+}
+
+// Policy is a signature policy, verified against the signatures of a local
+// signature store.
+type Policy struct {
+	defaultReq requirement
+	rules      []rule
+	store      *Store
+}
+
+// LoadPolicy loads the policy file at path. Signatures are looked up in
+// store.
+func LoadPolicy(path string, store *Store) (*Policy, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read signature policy")
+	}
+	var f policyFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse signature policy %s", path)
+	}
+	p, err := newPolicy(f, store)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid signature policy %s", path)
+	}
+	return p, nil
+}
+
+func newPolicy(f policyFile, store *Store) (*Policy, error) {
+	p := &Policy{store: store}
+	if f.Default.Action == "" {
+		f.Default.Action = ActionAllow
+	}
+	def, err := loadRequirement(f.Default)
+	if err != nil {
+		return nil, errors.Wrap(err, "default")
+	}
+	p.defaultReq = def
+
+	for _, r := range f.Rules {
+		pattern, prefix := r.Repository, false
+		if strings.HasSuffix(pattern, "/*") {
+			pattern, prefix = strings.TrimSuffix(pattern, "/*"), true
+		}
+		if pattern != "*" {
+			if err := validatePattern(pattern, prefix); err != nil {
+				return nil, err
+			}
+		}
+		req, err := loadRequirement(r.Requirement)
+		if err != nil {
+			return nil, errors.Wrap(err, r.Repository)
+		}
+		p.rules = append(p.rules, rule{pattern: pattern, prefix: prefix, requirement: req})
+	}
+	return p, nil
+}
+
+// validatePattern checks that pattern is a fully qualified repository name,
+// or the prefix of one if prefix is set.
+func validatePattern(pattern string, prefix bool) error {
+	repo := pattern
+	if prefix {
+		repo = pattern + "/x"
+	}
+	named, err := reference.ParseNamed(repo)
+	if err != nil {
+		return fmt.Errorf("%s is not a fully qualified repository name: %v", pattern, err)
+	}
+	if !reference.IsNameOnly(named) {
+		return fmt.Errorf("%s must not have a tag or digest", pattern)
+	}
+	return nil
+}
+
+func loadRequirement(r Requirement) (requirement, error) {
+	req := requirement{action: r.Action}
+	switch r.Action {
+	case ActionAllow, ActionReject:
+		if len(r.Keys) > 0 {
+			return req, fmt.Errorf("keys are only supported by %s", ActionRequireSignature)
+		}
+	case ActionRequireSignature:
+		if len(r.Keys) == 0 {
+			return req, fmt.Errorf("%s requires at least one key", ActionRequireSignature)
+		}
+		for _, path := range r.Keys {
+			key, err := loadPublicKey(path)
+			if err != nil {
+				return req, err
+			}
+			req.keys = append(req.keys, key)
+		}
+	default:
+		return req, fmt.Errorf("unknown action %q", r.Action)
+	}
+	return req, nil
+}
+
+// requirement returns the requirement of the first rule matching the
+// repository name, or the default requirement.
+func (p *Policy) requirement(name string) requirement {
+	for _, r := range p.rules {
+		if r.match(name) {
+			return r.requirement
+		}
+	}
+	return p.defaultReq
+}
+
+// Verify returns an error if the policy denies the image with the given
+// manifest digest from the repository of ref. An empty manifest digest is
+// only allowed by repositories which don't require signatures. The errors
+// returned are errdefs.Forbidden.
+func (p *Policy) Verify(ref reference.Named, manifestDigest digest.Digest) error {
+	var digests []digest.Digest
+	if manifestDigest != "" {
+		digests = append(digests, manifestDigest)
+	}
+	return p.VerifyDigests(ref, digests)
+}
+
+// VerifyDigests returns an error if the policy denies the image with the
+// given manifest digests from the repository of ref. A repository requiring
+// signatures is satisfied by a valid signature of any of the digests. If ref
+// is nil, the image is not in any repository, and only the default
+// requirement and the "*" rules apply.
+func (p *Policy) VerifyDigests(ref reference.Named, digests []digest.Digest) error {
+	var name, from string
+	if ref != nil {
+		name = ref.Name()
+		from = "images from " + name
+	} else {
+		from = "images without repository"
+	}
+	req := p.requirement(name)
+	switch req.action {
+	case ActionAllow:
+		return nil
+	case ActionReject:
+		return errdefs.Forbidden(fmt.Errorf("%s are rejected by the signature policy", from))
+	}
+
+	var lastErr error
+	for _, dgst := range digests {
+		err := p.verifySignatures(name, dgst, req.keys)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = errors.New("no manifest digest to verify")
+	}
+	return errdefs.Forbidden(errors.Wrapf(lastErr, "%s require a valid signature", from))
+}
+
+// verifySignatures returns nil if one of the signatures of dgst in the store
+// is valid for the repository name and one of the keys.
+func (p *Policy) verifySignatures(name string, dgst digest.Digest, keys []publicKey) error {
+	sigs, err := p.store.Signatures(dgst)
+	if err != nil {
+		return err
+	}
+	if len(sigs) == 0 {
+		return fmt.Errorf("no signature found for %s", dgst)
+	}
+	for _, sig := range sigs {
+		if err = sig.verify(name, dgst, keys); err == nil {
+			return nil
+		}
+	}
+	return errors.Wrapf(err, "no valid signature found for %s", dgst)
+}
@@ -0,0 +1,150 @@
+package signature // import "github.com/ellcrys/docker/image/signature"
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/distribution/reference"
+	"github.com/ellcrys/docker/errdefs"
+	"github.com/gotestyourself/gotestyourself/assert"
+	is "github.com/gotestyourself/gotestyourself/assert/cmp"
+	"github.com/opencontainers/go-digest"
+)
+
+func writePublicKey(t *testing.T, dir, name string, key crypto.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	assert.NilError(t, err)
+	p := filepath.Join(dir, name)
+	assert.NilError(t, ioutil.WriteFile(p, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644))
+	return p
+}
+
+func writeSignature(t *testing.T, root, name string, signer crypto.Signer, ref string, dgst digest.Digest) {
+	payload, err := json.Marshal(Identity{Reference: ref, ManifestDigest: dgst})
+	assert.NilError(t, err)
+
+	var sig []byte
+	if _, ok := signer.(ed25519.PrivateKey); ok {
+		sig, err = signer.Sign(rand.Reader, payload, crypto.Hash(0))
+	} else {
+		hashed := sha256.Sum256(payload)
+		sig, err = signer.Sign(rand.Reader, hashed[:], crypto.SHA256)
+	}
+	assert.NilError(t, err)
+
+	data, err := json.Marshal(Signature{Payload: payload, Signature: sig})
+	assert.NilError(t, err)
+	dir := filepath.Join(root, string(dgst.Algorithm()), dgst.Hex())
+	assert.NilError(t, os.MkdirAll(dir, 0755))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, name+".json"), data, 0644))
+}
+
+func TestPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signature-policy")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NilError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+
+	policyPath := filepath.Join(dir, "policy.json")
+	policy := policyFile{
+		Default: Requirement{Action: ActionReject},
+		Rules: []Rule{
+			{Repository: "registry.corp/public/*", Requirement: Requirement{Action: ActionAllow}},
+			{Repository: "registry.corp/*", Requirement: Requirement{
+				Action: ActionRequireSignature,
+				Keys: []string{
+					writePublicKey(t, dir, "ec.pem", ecKey.Public()),
+					writePublicKey(t, dir, "ed.pem", edPub),
+				},
+			}},
+			{Repository: "docker.io/library/alpine", Requirement: Requirement{Action: ActionAllow}},
+		},
+	}
+	data, err := json.Marshal(policy)
+	assert.NilError(t, err)
+	assert.NilError(t, ioutil.WriteFile(policyPath, data, 0644))
+
+	store := filepath.Join(dir, "signatures")
+	signed := digest.FromString("signed")
+	writeSignature(t, store, "ec", ecKey, "registry.corp/app:1.0", signed)
+	signedEd := digest.FromString("signed-ed25519")
+	writeSignature(t, store, "ed", edKey, "registry.corp/app", signedEd)
+	untrusted := digest.FromString("untrusted")
+	writeSignature(t, store, "other", otherKey, "registry.corp/app", untrusted)
+	otherRepo := digest.FromString("other-repo")
+	writeSignature(t, store, "ec", ecKey, "registry.corp/other", otherRepo)
+
+	p, err := LoadPolicy(policyPath, NewStore(store))
+	assert.NilError(t, err)
+
+	testCases := []struct {
+		ref    string
+		digest digest.Digest
+		err    string
+	}{
+		{ref: "alpine", digest: untrusted},
+		{ref: "registry.corp/public/busybox"},
+		{ref: "busybox", digest: signed, err: "images from docker.io/library/busybox are rejected by the signature policy"},
+		{ref: "registry.corp/app", digest: signed},
+		{ref: "registry.corp/app", digest: signedEd},
+		{ref: "registry.corp/app", err: "images from registry.corp/app require a valid signature: no manifest digest to verify"},
+		{ref: "registry.corp/app", digest: untrusted, err: "signature not made by a trusted key"},
+		{ref: "registry.corp/app", digest: otherRepo, err: "signature is for repository registry.corp/other"},
+		{ref: "registry.corp/app", digest: digest.FromString("unsigned"), err: "no signature found"},
+	}
+	for _, tc := range testCases {
+		ref, err := reference.ParseNormalizedNamed(tc.ref)
+		assert.NilError(t, err)
+		err = p.Verify(ref, tc.digest)
+		if tc.err == "" {
+			assert.Check(t, err, tc.ref)
+			continue
+		}
+		assert.Check(t, is.ErrorContains(err, tc.err), tc.ref)
+		assert.Check(t, errdefs.IsForbidden(err), tc.ref)
+	}
+
+	err = p.VerifyDigests(nil, nil)
+	assert.Check(t, is.Error(err, "images without repository are rejected by the signature policy"))
+}
+
+func TestLoadPolicyInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signature-policy")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	testCases := []struct {
+		policy string
+		err    string
+	}{
+		{policy: `{"default": {"action": "deny"}}`, err: `unknown action "deny"`},
+		{policy: `{"default": {"action": "require-signature"}}`, err: "require-signature requires at least one key"},
+		{policy: `{"rules": [{"repository": "alpine", "action": "allow"}]}`, err: "alpine is not a fully qualified repository name"},
+		{policy: `{"rules": [{"repository": "docker.io/library/alpine:3", "action": "allow"}]}`, err: "must not have a tag or digest"},
+		{policy: `{"rules": [{"repository": "*", "action": "reject", "keys": ["key.pem"]}]}`, err: "keys are only supported by require-signature"},
+		{policy: `{"rules": [{"repository": "*", "action": "require-signature", "keys": ["` + filepath.Join(dir, "policy.json") + `"]}]}`, err: "is not a PEM encoded public key"},
+	}
+	for _, tc := range testCases {
+		p := filepath.Join(dir, "policy.json")
+		assert.NilError(t, ioutil.WriteFile(p, []byte(tc.policy), 0644))
+		_, err := LoadPolicy(p, NewStore(dir))
+		assert.Check(t, is.ErrorContains(err, tc.err), tc.policy)
+	}
+}
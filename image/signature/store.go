@@ -0,0 +1,156 @@
+package signature // import "github.com/ellcrys/docker/image/signature"
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// Signature is a detached signature of an image manifest, as stored in the
+// signature store. The signature is made over Payload, the JSON encoding of
+// the signed identity.
+type Signature struct {
+	Payload   []byte `json:"payload"`
+	Signature []byte `json:"signature"`
+}
+
+// Identity is the payload of a signature: the repository and the digest of
+// the manifest signed.
+type Identity struct {
+	Reference      string        `json:"docker-reference"`
+	ManifestDigest digest.Digest `json:"docker-manifest-digest"`
+}
+
+// verify checks that the signature is made by one of the keys, for the
+// manifest digest in the repository name.
+func (s *Signature) verify(name string, dgst digest.Digest, keys []publicKey) error {
+	var verified bool
+	for _, key := range keys {
+		if key.verify(s.Payload, s.Signature) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return errors.New("signature not made by a trusted key")
+	}
+
+	var id Identity
+	if err := json.Unmarshal(s.Payload, &id); err != nil {
+		return errors.Wrap(err, "invalid signature payload")
+	}
+	if id.ManifestDigest != dgst {
+		return fmt.Errorf("signature is for manifest %s", id.ManifestDigest)
+	}
+	ref, err := reference.ParseNormalizedNamed(id.Reference)
+	if err != nil {
+		return errors.Wrap(err, "invalid signed reference")
+	}
+	if ref.Name() != name {
+		return fmt.Errorf("signature is for repository %s", ref.Name())
+	}
+	return nil
+}
+
+// Store is a local store of signatures. The signatures of a manifest are the
+// JSON files of the directory <root>/<algorithm>/<hex>, so that a manifest
+// may be signed by several keys.
+type Store struct {
+	root string
+}
+
+// NewStore returns a Store reading the signatures in root.
+func NewStore(root string) *Store {
+	return &Store{root: root}
+}
+
+// Signatures returns the signatures of the manifest with the given digest.
+func (s *Store) Signatures(dgst digest.Digest) ([]*Signature, error) {
+	if err := dgst.Validate(); err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(s.root, string(dgst.Algorithm()), dgst.Hex())
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to read signature store")
+	}
+	sort.Slice(fis, func(i, j int) bool { return fis[i].Name() < fis[j].Name() })
+
+	var sigs []*Signature
+	for _, fi := range fis {
+		if fi.IsDir() || !strings.HasSuffix(fi.Name(), ".json") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, fi.Name()))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read signature")
+		}
+		var sig Signature
+		if err := json.Unmarshal(data, &sig); err != nil {
+			return nil, errors.Wrapf(err, "invalid signature %s", filepath.Join(dir, fi.Name()))
+		}
+		sigs = append(sigs, &sig)
+	}
+	return sigs, nil
+}
+
+// publicKey is a RSA, ECDSA or Ed25519 public key trusted by a policy rule.
+type publicKey struct {
+	crypto.PublicKey
+}
+
+// loadPublicKey loads the PEM encoded PKIX public key at path.
+func loadPublicKey(path string) (publicKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return publicKey{}, errors.Wrap(err, "failed to read public key")
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return publicKey{}, fmt.Errorf("%s is not a PEM encoded public key", path)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return publicKey{}, errors.Wrapf(err, "invalid public key %s", path)
+	}
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+	default:
+		return publicKey{}, fmt.Errorf("unsupported public key type %T in %s", key, path)
+	}
+	return publicKey{key}, nil
+}
+
+// verify returns true if sig is a signature of payload by the key. RSA
+// signatures are PKCS #1 v1.5 and ECDSA signatures ASN.1 encoded, both of
+// the SHA-256 hash of the payload.
+func (k publicKey) verify(payload, sig []byte) bool {
+	hashed := sha256.Sum256(payload)
+	switch key := k.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], sig) == nil
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(key, hashed[:], sig)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, payload, sig)
+	}
+	return false
+}
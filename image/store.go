@@ -24,6 +24,8 @@ type Store interface {
 	GetParent(id ID) (ID, error)
 	SetLastUpdated(id ID) error
 	GetLastUpdated(id ID) (time.Time, error)
+	SetLastUsed(id ID) error
+	GetLastUsed(id ID) (time.Time, error)
 	Children(id ID) []ID
 	Map() map[ID]*Image
 	Heads() map[ID]*Image
@@ -293,6 +295,23 @@ func (is *store) GetLastUpdated(id ID) (time.Time, error) {
 	return time.Parse(time.RFC3339Nano, string(bytes))
 }
 
+// SetLastUsed time for the image ID to the current time
+func (is *store) SetLastUsed(id ID) error {
+	lastUsed := []byte(time.Now().Format(time.RFC3339Nano))
+	return is.fs.SetMetadata(id.Digest(), "lastUsed", lastUsed)
+}
+
+// GetLastUsed time for the image ID, which is the last time a container was
+// created from the image
+func (is *store) GetLastUsed(id ID) (time.Time, error) {
+	bytes, err := is.fs.GetMetadata(id.Digest(), "lastUsed")
+	if err != nil || len(bytes) == 0 {
+		// Never used
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, string(bytes))
+}
+
 func (is *store) Children(id ID) []ID {
 	is.RLock()
 	defer is.RUnlock()
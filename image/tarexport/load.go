@@ -24,6 +24,7 @@ import (
 	"github.com/ellcrys/docker/pkg/symlink"
 	"github.com/ellcrys/docker/pkg/system"
 	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/sirupsen/logrus"
 )
 
@@ -51,6 +52,9 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 	manifestFile, err := os.Open(manifestPath)
 	if err != nil {
 		if os.IsNotExist(err) {
+			if _, err := os.Stat(filepath.Join(tmpDir, ocispec.ImageLayoutFile)); err == nil {
+				return l.ociLoad(tmpDir, outStream, progressOutput)
+			}
 			return l.legacyLoad(tmpDir, outStream, progressOutput)
 		}
 		return err
@@ -67,61 +71,7 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 	var imageRefCount int
 
 	for _, m := range manifest {
-		configPath, err := safePath(tmpDir, m.Config)
-		if err != nil {
-			return err
-		}
-		config, err := ioutil.ReadFile(configPath)
-		if err != nil {
-			return err
-		}
-		img, err := image.NewFromJSON(config)
-		if err != nil {
-			return err
-		}
-		if err := checkCompatibleOS(img.OS); err != nil {
-			return err
-		}
-		rootFS := *img.RootFS
-		rootFS.DiffIDs = nil
-
-		if expected, actual := len(m.Layers), len(img.RootFS.DiffIDs); expected != actual {
-			return fmt.Errorf("invalid manifest, layers length mismatch: expected %d, got %d", expected, actual)
-		}
-
-		// On Windows, validate the platform, defaulting to windows if not present.
-		os := img.OS
-		if os == "" {
-			os = runtime.GOOS
-		}
-		if runtime.GOOS == "windows" {
-			if (os != "windows") && (os != "linux") {
-				return fmt.Errorf("configuration for this image has an unsupported operating system: %s", os)
-			}
-		}
-
-		for i, diffID := range img.RootFS.DiffIDs {
-			layerPath, err := safePath(tmpDir, m.Layers[i])
-			if err != nil {
-				return err
-			}
-			r := rootFS
-			r.Append(diffID)
-			newLayer, err := l.lss[os].Get(r.ChainID())
-			if err != nil {
-				newLayer, err = l.loadLayer(layerPath, rootFS, diffID.String(), os, m.LayerSources[diffID], progressOutput)
-				if err != nil {
-					return err
-				}
-			}
-			defer layer.ReleaseAndLog(l.lss[os], newLayer)
-			if expected, actual := diffID, newLayer.DiffID(); expected != actual {
-				return fmt.Errorf("invalid diffID for layer %d: expected %q, got %q", i, expected, actual)
-			}
-			rootFS.Append(diffID)
-		}
-
-		imgID, err := l.is.Create(config)
+		imgID, err := l.loadImage(tmpDir, m, progressOutput)
 		if err != nil {
 			return err
 		}
@@ -161,6 +111,66 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 	return nil
 }
 
+// loadImage loads the layers and the configuration of the image described by
+// a manifest item, and returns the ID of the image.
+func (l *tarexporter) loadImage(tmpDir string, m manifestItem, progressOutput progress.Output) (image.ID, error) {
+	configPath, err := safePath(tmpDir, m.Config)
+	if err != nil {
+		return "", err
+	}
+	config, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return "", err
+	}
+	img, err := image.NewFromJSON(config)
+	if err != nil {
+		return "", err
+	}
+	if err := checkCompatibleOS(img.OS); err != nil {
+		return "", err
+	}
+	rootFS := *img.RootFS
+	rootFS.DiffIDs = nil
+
+	if expected, actual := len(m.Layers), len(img.RootFS.DiffIDs); expected != actual {
+		return "", fmt.Errorf("invalid manifest, layers length mismatch: expected %d, got %d", expected, actual)
+	}
+
+	// On Windows, validate the platform, defaulting to windows if not present.
+	os := img.OS
+	if os == "" {
+		os = runtime.GOOS
+	}
+	if runtime.GOOS == "windows" {
+		if (os != "windows") && (os != "linux") {
+			return "", fmt.Errorf("configuration for this image has an unsupported operating system: %s", os)
+		}
+	}
+
+	for i, diffID := range img.RootFS.DiffIDs {
+		layerPath, err := safePath(tmpDir, m.Layers[i])
+		if err != nil {
+			return "", err
+		}
+		r := rootFS
+		r.Append(diffID)
+		newLayer, err := l.lss[os].Get(r.ChainID())
+		if err != nil {
+			newLayer, err = l.loadLayer(layerPath, rootFS, diffID.String(), os, m.LayerSources[diffID], progressOutput)
+			if err != nil {
+				return "", err
+			}
+		}
+		defer layer.ReleaseAndLog(l.lss[os], newLayer)
+		if expected, actual := diffID, newLayer.DiffID(); expected != actual {
+			return "", fmt.Errorf("invalid diffID for layer %d: expected %q, got %q", i, expected, actual)
+		}
+		rootFS.Append(diffID)
+	}
+
+	return l.is.Create(config)
+}
+
 func (l *tarexporter) setParentID(id, parentID image.ID) error {
 	img, err := l.is.Get(id)
 	if err != nil {
@@ -0,0 +1,319 @@
+package tarexport // import "github.com/ellcrys/docker/image/tarexport"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/distribution/reference"
+	"github.com/ellcrys/docker/image"
+	"github.com/ellcrys/docker/layer"
+	"github.com/ellcrys/docker/pkg/progress"
+	"github.com/ellcrys/docker/pkg/system"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// saveImageOCI writes the configuration, the layers and the manifest of an
+// image as blobs of the OCI image layout, and returns the descriptor of the
+// manifest.
+func (s *saveSession) saveImageOCI(id image.ID) (ocispec.Descriptor, map[layer.DiffID]distribution.Descriptor, error) {
+	img := s.images[id].image
+	if len(img.RootFS.DiffIDs) == 0 {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("empty export - not implemented")
+	}
+	operatingSystem := img.OS
+	if operatingSystem == "" {
+		operatingSystem = runtime.GOOS
+	}
+
+	manifest := ocispec.Manifest{Versioned: specs.Versioned{SchemaVersion: 2}}
+	var layers []string
+	var foreignSrcs map[layer.DiffID]distribution.Descriptor
+
+	rootFS := *img.RootFS
+	rootFS.DiffIDs = nil
+	for _, diffID := range img.RootFS.DiffIDs {
+		rootFS.Append(diffID)
+		desc, src, err := s.saveLayerOCI(rootFS.ChainID(), operatingSystem)
+		if err != nil {
+			return ocispec.Descriptor{}, nil, err
+		}
+		manifest.Layers = append(manifest.Layers, desc)
+		layers = append(layers, blobPath(desc.Digest))
+		if src.Digest != "" {
+			if foreignSrcs == nil {
+				foreignSrcs = make(map[layer.DiffID]distribution.Descriptor)
+			}
+			foreignSrcs[diffID] = src
+		}
+	}
+
+	config, err := s.writeBlob(ocispec.MediaTypeImageConfig, bytes.NewReader(img.RawJSON()))
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	manifest.Config = config
+
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	desc, err := s.writeBlob(ocispec.MediaTypeImageManifest, bytes.NewReader(b))
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	desc.Platform = &ocispec.Platform{
+		Architecture: img.Architecture,
+		OS:           operatingSystem,
+		OSVersion:    img.OSVersion,
+		OSFeatures:   img.OSFeatures,
+	}
+
+	s.images[id].layers = layers
+	return desc, foreignSrcs, nil
+}
+
+// saveLayerOCI writes the uncompressed tar stream of a layer as a blob, unless
+// the layer was already saved.
+func (s *saveSession) saveLayerOCI(id layer.ChainID, operatingSystem string) (ocispec.Descriptor, distribution.Descriptor, error) {
+	l, err := s.lss[operatingSystem].Get(id)
+	if err != nil {
+		return ocispec.Descriptor{}, distribution.Descriptor{}, err
+	}
+	defer layer.ReleaseAndLog(s.lss[operatingSystem], l)
+
+	var src distribution.Descriptor
+	if fs, ok := l.(distribution.Describable); ok {
+		src = fs.Descriptor()
+	}
+
+	if desc, exists := s.layerBlobs[l.DiffID()]; exists {
+		return desc, src, nil
+	}
+
+	arch, err := l.TarStream()
+	if err != nil {
+		return ocispec.Descriptor{}, distribution.Descriptor{}, err
+	}
+	defer arch.Close()
+
+	desc, err := s.writeBlob(ocispec.MediaTypeImageLayer, arch)
+	if err != nil {
+		return ocispec.Descriptor{}, distribution.Descriptor{}, err
+	}
+	s.layerBlobs[l.DiffID()] = desc
+	return desc, src, nil
+}
+
+// writeBlob writes the content of r to the blobs directory of the OCI image
+// layout, under its digest.
+func (s *saveSession) writeBlob(mediaType string, r io.Reader) (ocispec.Descriptor, error) {
+	dir := filepath.Join(s.outDir, ociBlobsDir, string(digest.Canonical))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	// Use system.CreateSequential rather than os.Create. This ensures sequential
+	// file access on Windows to avoid eating into MM standby list.
+	tmpPath := filepath.Join(dir, ".tmp")
+	f, err := system.CreateSequential(tmpPath)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	digester := digest.Canonical.Digester()
+	size, err := io.Copy(io.MultiWriter(f, digester.Hash()), r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return ocispec.Descriptor{}, err
+	}
+
+	dgst := digester.Digest()
+	p := filepath.Join(dir, dgst.Hex())
+	if err := os.Rename(tmpPath, p); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if err := system.Chtimes(p, time.Unix(0, 0), time.Unix(0, 0)); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    dgst,
+		Size:      size,
+	}, nil
+}
+
+func writeJSONFile(p string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(p, b, 0644); err != nil {
+		return err
+	}
+	return system.Chtimes(p, time.Unix(0, 0), time.Unix(0, 0))
+}
+
+// blobPath returns the path of a blob in an OCI image layout. It uses path,
+// not filepath, as the paths are also written to manifest.json.
+func blobPath(dgst digest.Digest) string {
+	return path.Join(ociBlobsDir, dgst.Algorithm().String(), dgst.Hex())
+}
+
+// ociRefDescriptor returns the descriptor of a manifest in the index of an
+// OCI image layout, annotated with a reference of the image.
+func ociRefDescriptor(desc ocispec.Descriptor, ref reference.NamedTagged) ocispec.Descriptor {
+	desc.Annotations = map[string]string{
+		containerdImageNameAnnotation: ref.String(),
+		ocispec.AnnotationRefName:     ref.Tag(),
+	}
+	return desc
+}
+
+// ociImageRef returns the reference of an image annotated in the index of an
+// OCI image layout, or nil if the image is not tagged.
+func ociImageRef(desc ocispec.Descriptor) reference.NamedTagged {
+	for _, name := range []string{desc.Annotations[containerdImageNameAnnotation], desc.Annotations[ocispec.AnnotationRefName]} {
+		if name == "" {
+			continue
+		}
+		named, err := reference.ParseNormalizedNamed(name)
+		if err != nil {
+			continue
+		}
+		if tagged, ok := named.(reference.NamedTagged); ok {
+			return tagged
+		}
+	}
+	return nil
+}
+
+// ociLoad loads the images of an OCI image layout. The images of multi-platform
+// indexes are loaded for the platform of the daemon.
+func (l *tarexporter) ociLoad(tmpDir string, outStream io.Writer, progressOutput progress.Output) error {
+	indexPath, err := safePath(tmpDir, ociIndexFileName)
+	if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadFile(indexPath)
+	if err != nil {
+		return err
+	}
+	var index ocispec.Index
+	if err := json.Unmarshal(b, &index); err != nil {
+		return errors.Wrap(err, "invalid OCI image index")
+	}
+
+	matcher, err := platforms.Parse(platforms.Default())
+	if err != nil {
+		return err
+	}
+
+	var imageIDsStr string
+	var imageRefCount int
+	loaded := make(map[digest.Digest]image.ID)
+
+	for _, desc := range index.Manifests {
+		manifest, err := resolveOCIManifest(tmpDir, desc, matcher)
+		if err != nil {
+			return err
+		}
+
+		imgID, ok := loaded[manifest.Config.Digest]
+		if !ok {
+			if _, err := readOCIBlob(tmpDir, manifest.Config); err != nil {
+				return err
+			}
+			m := manifestItem{Config: blobPath(manifest.Config.Digest)}
+			for _, layerDesc := range manifest.Layers {
+				m.Layers = append(m.Layers, blobPath(layerDesc.Digest))
+			}
+			imgID, err = l.loadImage(tmpDir, m, progressOutput)
+			if err != nil {
+				return err
+			}
+			loaded[manifest.Config.Digest] = imgID
+			imageIDsStr += fmt.Sprintf("Loaded image ID: %s\n", imgID)
+			l.loggerImgEvent.LogImageEvent(imgID.String(), imgID.String(), "load")
+		}
+
+		if ref := ociImageRef(desc); ref != nil {
+			l.setLoadedTag(ref, imgID.Digest(), outStream)
+			outStream.Write([]byte(fmt.Sprintf("Loaded image: %s\n", reference.FamiliarString(ref))))
+			imageRefCount++
+		}
+	}
+
+	if imageRefCount == 0 {
+		outStream.Write([]byte(imageIDsStr))
+	}
+	return nil
+}
+
+// resolveOCIManifest returns the image manifest referenced by desc, selecting
+// the manifest of the platform matched by matcher if desc references an image
+// index or a manifest list.
+func resolveOCIManifest(dir string, desc ocispec.Descriptor, matcher platforms.Matcher) (ocispec.Manifest, error) {
+	b, err := readOCIBlob(dir, desc)
+	if err != nil {
+		return ocispec.Manifest{}, err
+	}
+
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageManifest, schema2.MediaTypeManifest:
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(b, &manifest); err != nil {
+			return ocispec.Manifest{}, errors.Wrapf(err, "invalid image manifest %s", desc.Digest)
+		}
+		return manifest, nil
+	case ocispec.MediaTypeImageIndex, manifestlist.MediaTypeManifestList:
+		var index ocispec.Index
+		if err := json.Unmarshal(b, &index); err != nil {
+			return ocispec.Manifest{}, errors.Wrapf(err, "invalid image index %s", desc.Digest)
+		}
+		for _, m := range index.Manifests {
+			if m.Platform != nil && matcher.Match(*m.Platform) {
+				return resolveOCIManifest(dir, m, matcher)
+			}
+		}
+		return ocispec.Manifest{}, errors.Errorf("no manifest for platform %s in image index %s", platforms.Format(matcher.Spec()), desc.Digest)
+	default:
+		return ocispec.Manifest{}, errors.Errorf("unsupported media type %q for manifest %s", desc.MediaType, desc.Digest)
+	}
+}
+
+// readOCIBlob returns the content of a blob of an OCI image layout, verifying
+// its digest.
+func readOCIBlob(dir string, desc ocispec.Descriptor) ([]byte, error) {
+	if err := desc.Digest.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid descriptor")
+	}
+	p, err := safePath(dir, blobPath(desc.Digest))
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	if actual := desc.Digest.Algorithm().FromBytes(b); actual != desc.Digest {
+		return nil, errors.Errorf("invalid blob %s: got digest %s", desc.Digest, actual)
+	}
+	return b, nil
+}
@@ -0,0 +1,211 @@
+package tarexport // import "github.com/ellcrys/docker/image/tarexport"
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/distribution/reference"
+	_ "github.com/ellcrys/docker/daemon/graphdriver/vfs"
+	"github.com/ellcrys/docker/errdefs"
+	"github.com/ellcrys/docker/image"
+	"github.com/ellcrys/docker/layer"
+	"github.com/ellcrys/docker/pkg/archive"
+	"github.com/ellcrys/docker/pkg/idtools"
+	refstore "github.com/ellcrys/docker/reference"
+	"github.com/gotestyourself/gotestyourself/assert"
+	is "github.com/gotestyourself/gotestyourself/assert/cmp"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func writeTestBlob(t *testing.T, dir, mediaType string, v interface{}) ocispec.Descriptor {
+	b, err := json.Marshal(v)
+	assert.NilError(t, err)
+	dgst := digest.FromBytes(b)
+	p := filepath.Join(dir, filepath.FromSlash(blobPath(dgst)))
+	assert.NilError(t, os.MkdirAll(filepath.Dir(p), 0755))
+	assert.NilError(t, ioutil.WriteFile(p, b, 0644))
+	return ocispec.Descriptor{MediaType: mediaType, Digest: dgst, Size: int64(len(b))}
+}
+
+func TestResolveOCIManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tarexport-oci")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	newManifest := func(config string) ocispec.Manifest {
+		return ocispec.Manifest{
+			Versioned: specs.Versioned{SchemaVersion: 2},
+			Config:    ocispec.Descriptor{MediaType: ocispec.MediaTypeImageConfig, Digest: digest.FromString(config)},
+		}
+	}
+	amd64 := writeTestBlob(t, dir, ocispec.MediaTypeImageManifest, newManifest("amd64"))
+	amd64.Platform = &ocispec.Platform{OS: "linux", Architecture: "amd64"}
+	arm64 := writeTestBlob(t, dir, ocispec.MediaTypeImageManifest, newManifest("arm64"))
+	arm64.Platform = &ocispec.Platform{OS: "linux", Architecture: "arm64"}
+	index := writeTestBlob(t, dir, ocispec.MediaTypeImageIndex, ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Manifests: []ocispec.Descriptor{amd64, arm64},
+	})
+
+	matcher, err := platforms.Parse("linux/arm64")
+	assert.NilError(t, err)
+
+	manifest, err := resolveOCIManifest(dir, index, matcher)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(digest.FromString("arm64"), manifest.Config.Digest))
+
+	manifest, err = resolveOCIManifest(dir, amd64, matcher)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(digest.FromString("amd64"), manifest.Config.Digest))
+
+	matcher, err = platforms.Parse("linux/s390x")
+	assert.NilError(t, err)
+	_, err = resolveOCIManifest(dir, index, matcher)
+	assert.Check(t, is.ErrorContains(err, "no manifest for platform linux/s390x"))
+
+	invalid := amd64
+	invalid.Digest = digest.FromString("invalid")
+	_, err = resolveOCIManifest(dir, invalid, matcher)
+	assert.Check(t, os.IsNotExist(err))
+
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, filepath.FromSlash(blobPath(invalid.Digest))), []byte("{}"), 0644))
+	_, err = resolveOCIManifest(dir, invalid, matcher)
+	assert.Check(t, is.ErrorContains(err, "invalid blob"))
+}
+
+func TestOCIImageRef(t *testing.T) {
+	named, err := reference.ParseNormalizedNamed("busybox:1.28")
+	assert.NilError(t, err)
+	desc := ociRefDescriptor(ocispec.Descriptor{}, named.(reference.NamedTagged))
+	assert.Check(t, is.Equal("docker.io/library/busybox:1.28", desc.Annotations[containerdImageNameAnnotation]))
+	assert.Check(t, is.Equal("1.28", desc.Annotations[ocispec.AnnotationRefName]))
+
+	ref := ociImageRef(desc)
+	assert.Assert(t, ref != nil)
+	assert.Check(t, is.Equal("busybox:1.28", reference.FamiliarString(ref)))
+
+	ref = ociImageRef(ocispec.Descriptor{Annotations: map[string]string{ocispec.AnnotationRefName: "example.com/foo:bar"}})
+	assert.Assert(t, ref != nil)
+	assert.Check(t, is.Equal("example.com/foo:bar", reference.FamiliarString(ref)))
+
+	assert.Check(t, ociImageRef(ocispec.Descriptor{Annotations: map[string]string{ocispec.AnnotationRefName: "latest"}}) == nil)
+	assert.Check(t, ociImageRef(ocispec.Descriptor{}) == nil)
+}
+
+type nopEventLogger struct{}
+
+func (nopEventLogger) LogImageEvent(imageID, refName, action string) {}
+
+func newTestExporter(t *testing.T, root string) (*tarexporter, func()) {
+	ls, err := layer.NewStoreFromOptions(layer.StoreOptions{
+		Root:                      filepath.Join(root, "vfs"),
+		MetadataStorePathTemplate: filepath.Join(root, "image", "%s", "layerdb"),
+		GraphDriver:               "vfs",
+		IDMappings:                &idtools.IDMappings{},
+		OS:                        runtime.GOOS,
+	})
+	assert.NilError(t, err)
+	lss := map[string]layer.Store{runtime.GOOS: ls}
+
+	fs, err := image.NewFSStoreBackend(filepath.Join(root, "imagedb"))
+	assert.NilError(t, err)
+	is, err := image.NewImageStore(fs, map[string]image.LayerGetReleaser{runtime.GOOS: ls})
+	assert.NilError(t, err)
+	rs, err := refstore.NewReferenceStore(filepath.Join(root, "repositories.json"))
+	assert.NilError(t, err)
+
+	return &tarexporter{is: is, lss: lss, rs: rs, loggerImgEvent: nopEventLogger{}}, func() {
+		ls.Cleanup()
+	}
+}
+
+func TestSaveLoadOCI(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("vfs layers require privileges on windows")
+	}
+	dir, err := ioutil.TempDir("", "tarexport-oci")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	src, cleanup := newTestExporter(t, filepath.Join(dir, "src"))
+	defer cleanup()
+
+	layerTar, err := archive.Generate("hello", "world")
+	assert.NilError(t, err)
+	l, err := src.lss[runtime.GOOS].Register(layerTar, "")
+	assert.NilError(t, err)
+	defer src.lss[runtime.GOOS].Release(l)
+
+	config, err := json.Marshal(&image.Image{
+		V1Image: image.V1Image{OS: runtime.GOOS, Architecture: runtime.GOARCH},
+		RootFS:  &image.RootFS{Type: "layers", DiffIDs: []layer.DiffID{l.DiffID()}},
+	})
+	assert.NilError(t, err)
+	id, err := src.is.Create(config)
+	assert.NilError(t, err)
+	named, err := reference.ParseNormalizedNamed("example.com/hello:world")
+	assert.NilError(t, err)
+	assert.NilError(t, src.rs.AddTag(named, id.Digest(), false))
+
+	var archive bytes.Buffer
+	assert.NilError(t, src.Save([]string{"example.com/hello:world"}, FormatOCI, &archive))
+
+	var entries []string
+	tr := tar.NewReader(bytes.NewReader(archive.Bytes()))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NilError(t, err)
+		entries = append(entries, hdr.Name)
+	}
+	assert.Check(t, is.Contains(entries, ocispec.ImageLayoutFile))
+	assert.Check(t, is.Contains(entries, ociIndexFileName))
+	assert.Check(t, is.Contains(entries, manifestFileName))
+	assert.Check(t, is.Contains(entries, blobPath(id.Digest())))
+	assert.Check(t, is.Contains(entries, blobPath(digest.Digest(l.DiffID()))))
+
+	err = src.Save([]string{"example.com/hello:world"}, "invalid", ioutil.Discard)
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+
+	// Load the layout without manifest.json, as written by OCI tooling.
+	var layout bytes.Buffer
+	tw := tar.NewWriter(&layout)
+	tr = tar.NewReader(bytes.NewReader(archive.Bytes()))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NilError(t, err)
+		if hdr.Name == manifestFileName {
+			continue
+		}
+		assert.NilError(t, tw.WriteHeader(hdr))
+		_, err = io.Copy(tw, tr)
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+
+	dst, cleanup := newTestExporter(t, filepath.Join(dir, "dst"))
+	defer cleanup()
+
+	var out bytes.Buffer
+	assert.NilError(t, dst.Load(ioutil.NopCloser(&layout), &out, true))
+	assert.Check(t, is.Contains(out.String(), "Loaded image: example.com/hello:world"))
+
+	loadedID, err := dst.rs.Get(named)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(id.Digest(), loadedID))
+}
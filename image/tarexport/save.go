@@ -13,12 +13,15 @@ import (
 
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/reference"
+	"github.com/ellcrys/docker/errdefs"
 	"github.com/ellcrys/docker/image"
 	"github.com/ellcrys/docker/image/v1"
 	"github.com/ellcrys/docker/layer"
 	"github.com/ellcrys/docker/pkg/archive"
 	"github.com/ellcrys/docker/pkg/system"
 	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 )
 
@@ -32,12 +35,22 @@ type imageDescriptor struct {
 type saveSession struct {
 	*tarexporter
 	outDir      string
+	format      string
 	images      map[image.ID]*imageDescriptor
 	savedLayers map[string]struct{}
 	diffIDPaths map[layer.DiffID]string // cache every diffID blob to avoid duplicates
+	layerBlobs  map[layer.DiffID]ocispec.Descriptor
 }
 
-func (l *tarexporter) Save(names []string, outStream io.Writer) error {
+func (l *tarexporter) Save(names []string, format string, outStream io.Writer) error {
+	switch format {
+	case "":
+		format = FormatDocker
+	case FormatDocker, FormatOCI:
+	default:
+		return errdefs.InvalidParameter(errors.Errorf("invalid format %q, must be %q or %q", format, FormatDocker, FormatOCI))
+	}
+
 	images, err := l.parseNames(names)
 	if err != nil {
 		return err
@@ -45,7 +58,7 @@ func (l *tarexporter) Save(names []string, outStream io.Writer) error {
 
 	// Release all the image top layer references
 	defer l.releaseLayerReferences(images)
-	return (&saveSession{tarexporter: l, images: images}).save(outStream)
+	return (&saveSession{tarexporter: l, format: format, images: images}).save(outStream)
 }
 
 // parseNames will parse the image names to a map which contains image.ID to *imageDescriptor.
@@ -187,6 +200,7 @@ func (l *tarexporter) releaseLayerReferences(imgDescr map[image.ID]*imageDescrip
 func (s *saveSession) save(outStream io.Writer) error {
 	s.savedLayers = make(map[string]struct{})
 	s.diffIDPaths = make(map[layer.DiffID]string)
+	s.layerBlobs = make(map[layer.DiffID]ocispec.Descriptor)
 
 	// get image json
 	tempDir, err := ioutil.TempDir("", "docker-export-")
@@ -200,39 +214,63 @@ func (s *saveSession) save(outStream io.Writer) error {
 
 	var manifest []manifestItem
 	var parentLinks []parentLink
+	var index []ocispec.Descriptor
 
 	for id, imageDescr := range s.images {
-		foreignSrcs, err := s.saveImage(id)
-		if err != nil {
-			return err
-		}
+		if s.format == FormatOCI {
+			desc, foreignSrcs, err := s.saveImageOCI(id)
+			if err != nil {
+				return err
+			}
 
-		var repoTags []string
-		var layers []string
+			var repoTags []string
+			for _, ref := range imageDescr.refs {
+				index = append(index, ociRefDescriptor(desc, ref))
+				repoTags = append(repoTags, reference.FamiliarString(ref))
+			}
+			if len(imageDescr.refs) == 0 {
+				index = append(index, desc)
+			}
 
-		for _, ref := range imageDescr.refs {
-			familiarName := reference.FamiliarName(ref)
-			if _, ok := reposLegacy[familiarName]; !ok {
-				reposLegacy[familiarName] = make(map[string]string)
+			manifest = append(manifest, manifestItem{
+				Config:       blobPath(id.Digest()),
+				RepoTags:     repoTags,
+				Layers:       imageDescr.layers,
+				LayerSources: foreignSrcs,
+			})
+		} else {
+			foreignSrcs, err := s.saveImage(id)
+			if err != nil {
+				return err
 			}
-			reposLegacy[familiarName][ref.Tag()] = imageDescr.layers[len(imageDescr.layers)-1]
-			repoTags = append(repoTags, reference.FamiliarString(ref))
-		}
 
-		for _, l := range imageDescr.layers {
-			// IMPORTANT: We use path, not filepath here to ensure the layers
-			// in the manifest use Unix-style forward-slashes. Otherwise, a
-			// Linux image saved from LCOW won't be able to be imported on
-			// LCOL.
-			layers = append(layers, path.Join(l, legacyLayerFileName))
-		}
+			var repoTags []string
+			var layers []string
+
+			for _, ref := range imageDescr.refs {
+				familiarName := reference.FamiliarName(ref)
+				if _, ok := reposLegacy[familiarName]; !ok {
+					reposLegacy[familiarName] = make(map[string]string)
+				}
+				reposLegacy[familiarName][ref.Tag()] = imageDescr.layers[len(imageDescr.layers)-1]
+				repoTags = append(repoTags, reference.FamiliarString(ref))
+			}
 
-		manifest = append(manifest, manifestItem{
-			Config:       id.Digest().Hex() + ".json",
-			RepoTags:     repoTags,
-			Layers:       layers,
-			LayerSources: foreignSrcs,
-		})
+			for _, l := range imageDescr.layers {
+				// IMPORTANT: We use path, not filepath here to ensure the layers
+				// in the manifest use Unix-style forward-slashes. Otherwise, a
+				// Linux image saved from LCOW won't be able to be imported on
+				// LCOL.
+				layers = append(layers, path.Join(l, legacyLayerFileName))
+			}
+
+			manifest = append(manifest, manifestItem{
+				Config:       id.Digest().Hex() + ".json",
+				RepoTags:     repoTags,
+				Layers:       layers,
+				LayerSources: foreignSrcs,
+			})
+		}
 
 		parentID, _ := s.is.GetParent(id)
 		parentLinks = append(parentLinks, parentLink{id, parentID})
@@ -264,6 +302,18 @@ func (s *saveSession) save(outStream io.Writer) error {
 		}
 	}
 
+	if s.format == FormatOCI {
+		if err := writeJSONFile(filepath.Join(tempDir, ocispec.ImageLayoutFile), ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion}); err != nil {
+			return err
+		}
+		if err := writeJSONFile(filepath.Join(tempDir, ociIndexFileName), ocispec.Index{
+			Versioned: specs.Versioned{SchemaVersion: 2},
+			Manifests: index,
+		}); err != nil {
+			return err
+		}
+	}
+
 	manifestFileName := filepath.Join(tempDir, manifestFileName)
 	f, err := os.OpenFile(manifestFileName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
 	if err != nil {
@@ -13,6 +13,24 @@ const (
 	legacyConfigFileName       = "json"
 	legacyVersionFileName      = "VERSION"
 	legacyRepositoriesFileName = "repositories"
+	ociIndexFileName           = "index.json"
+	ociBlobsDir                = "blobs"
+
+	// containerdImageNameAnnotation is the annotation of the manifests of
+	// an OCI image index holding the full reference of the image, as set by
+	// containerd.
+	containerdImageNameAnnotation = "io.containerd.image.name"
+)
+
+const (
+	// FormatDocker is the format of the archives written by Save by default,
+	// holding a manifest.json file, the configurations of the images, and a
+	// directory per layer.
+	FormatDocker = "docker"
+	// FormatOCI is the format of the archives holding an OCI image layout.
+	// The archives also hold a manifest.json file, so that they can be
+	// loaded by older daemons.
+	FormatOCI = "oci"
 )
 
 type manifestItem struct {
@@ -95,6 +95,8 @@ const (
 	Gzip
 	// Xz is xz compression algorithm.
 	Xz
+	// Zstd is zstd compression algorithm.
+	Zstd
 )
 
 const (
@@ -138,6 +140,7 @@ func DetectCompression(source []byte) Compression {
 		Bzip2: {0x42, 0x5A, 0x68},
 		Gzip:  {0x1F, 0x8B, 0x08},
 		Xz:    {0xFD, 0x37, 0x7A, 0x58, 0x5A, 0x00},
+		Zstd:  {0x28, 0xB5, 0x2F, 0xFD},
 	} {
 		if len(source) < len(m) {
 			logrus.Debug("Len too short")
@@ -156,6 +159,12 @@ func xzDecompress(ctx context.Context, archive io.Reader) (io.ReadCloser, error)
 	return cmdStream(exec.CommandContext(ctx, args[0], args[1:]...), archive)
 }
 
+func zstdDecompress(ctx context.Context, archive io.Reader) (io.ReadCloser, error) {
+	args := []string{"zstd", "-d", "-c", "-q"}
+
+	return cmdStream(exec.CommandContext(ctx, args[0], args[1:]...), archive)
+}
+
 func gzDecompress(ctx context.Context, buf io.Reader) (io.ReadCloser, error) {
 	if unpigzPath == "" {
 		return gzip.NewReader(buf)
@@ -224,6 +233,16 @@ func DecompressStream(archive io.Reader) (io.ReadCloser, error) {
 		}
 		readBufWrapper := p.NewReadCloserWrapper(buf, xzReader)
 		return wrapReadCloser(readBufWrapper, cancel), nil
+	case Zstd:
+		ctx, cancel := context.WithCancel(context.Background())
+
+		zstdReader, err := zstdDecompress(ctx, buf)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		readBufWrapper := p.NewReadCloserWrapper(buf, zstdReader)
+		return wrapReadCloser(readBufWrapper, cancel), nil
 	default:
 		return nil, fmt.Errorf("Unsupported compression format %s", (&compression).Extension())
 	}
@@ -241,6 +260,13 @@ func CompressStream(dest io.Writer, compression Compression) (io.WriteCloser, er
 		gzWriter := gzip.NewWriter(dest)
 		writeBufWrapper := p.NewWriteCloserWrapper(buf, gzWriter)
 		return writeBufWrapper, nil
+	case Zstd:
+		zstdWriter, err := cmdWriteStream(exec.Command("zstd", "-c", "-q"), dest)
+		if err != nil {
+			return nil, err
+		}
+		writeBufWrapper := p.NewWriteCloserWrapper(buf, zstdWriter)
+		return writeBufWrapper, nil
 	case Bzip2, Xz:
 		// archive/bzip2 does not support writing, and there is no xz support at all
 		// However, this is not a problem as docker only currently generates gzipped tars
@@ -348,6 +374,8 @@ func (compression *Compression) Extension() string {
 		return "tar.gz"
 	case Xz:
 		return "tar.xz"
+	case Zstd:
+		return "tar.zst"
 	}
 	return ""
 }
@@ -1025,7 +1053,7 @@ loop:
 // Untar reads a stream of bytes from `archive`, parses it as a tar archive,
 // and unpacks it into the directory at `dest`.
 // The archive may be compressed with one of the following algorithms:
-//  identity (uncompressed), gzip, bzip2, xz.
+//  identity (uncompressed), gzip, bzip2, xz, zstd.
 // FIXME: specify behavior when target path exists vs. doesn't exist.
 func Untar(tarArchive io.Reader, dest string, options *TarOptions) error {
 	return untarHandler(tarArchive, dest, options, true)
@@ -1236,6 +1264,38 @@ func cmdStream(cmd *exec.Cmd, input io.Reader) (io.ReadCloser, error) {
 	return pipeR, nil
 }
 
+// cmdWriteStream executes a command, and returns a WriteCloser which feeds
+// the command's stdin. The command's stdout is written to output. Closing the
+// returned WriteCloser waits for the command to exit, and reports any error
+// it encountered.
+func cmdWriteStream(cmd *exec.Cmd, output io.Writer) (io.WriteCloser, error) {
+	pipeR, pipeW := io.Pipe()
+	cmd.Stdin = pipeR
+	cmd.Stdout = output
+	var errBuf bytes.Buffer
+	cmd.Stderr = &errBuf
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		if err != nil {
+			err = fmt.Errorf("%s: %s", err, errBuf.String())
+		}
+		// Unblock any pending writes if the command exited early
+		pipeR.CloseWithError(err)
+		done <- err
+	}()
+
+	return ioutils.NewWriteCloserWrapper(pipeW, func() error {
+		pipeW.Close()
+		return <-done
+	}), nil
+}
+
 // NewTempArchive reads the content of src into a temporary file, and returns the contents
 // of that file as an archive. The archive can only be read once - as soon as reading completes,
 // the file will be deleted.
@@ -134,6 +134,48 @@ func TestDecompressStreamXz(t *testing.T) {
 	testDecompressStream(t, "xz", "xz -f")
 }
 
+func TestDecompressStreamZstd(t *testing.T) {
+	if _, err := exec.LookPath("zstd"); err != nil {
+		t.Skip("zstd binary not found in PATH")
+	}
+	testDecompressStream(t, "zst", "zstd -f -q")
+}
+
+func TestCompressStreamZstd(t *testing.T) {
+	if _, err := exec.LookPath("zstd"); err != nil {
+		t.Skip("zstd binary not found in PATH")
+	}
+	content := []byte("hello zstd")
+
+	var dest bytes.Buffer
+	w, err := CompressStream(&dest, Zstd)
+	if err != nil {
+		t.Fatalf("Failed to create the zstd compression stream: %v", err)
+	}
+	if _, err := w.Write(content); err != nil {
+		t.Fatalf("Failed to write to the zstd compression stream: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close the zstd compression stream: %v", err)
+	}
+	if compression := DetectCompression(dest.Bytes()); compression != Zstd {
+		t.Fatalf("Expected zstd compression to be detected, got %s", (&compression).Extension())
+	}
+
+	r, err := DecompressStream(&dest)
+	if err != nil {
+		t.Fatalf("Failed to decompress the zstd stream: %v", err)
+	}
+	defer r.Close()
+	out, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Failed to read the decompressed stream: %v", err)
+	}
+	if !bytes.Equal(out, content) {
+		t.Fatalf("Expected %q after decompression, got %q", content, out)
+	}
+}
+
 func TestCompressStreamXzUnsupported(t *testing.T) {
 	dest, err := os.Create(tmp + "dest")
 	if err != nil {
@@ -209,6 +251,13 @@ func TestExtensionXz(t *testing.T) {
 		t.Fatalf("The extension of a xz archive should be 'tar.xz'")
 	}
 }
+func TestExtensionZstd(t *testing.T) {
+	compression := Zstd
+	output := compression.Extension()
+	if output != "tar.zst" {
+		t.Fatalf("The extension of a zstd archive should be 'tar.zst'")
+	}
+}
 
 func TestCmdStreamLargeStderr(t *testing.T) {
 	cmd := exec.Command("sh", "-c", "dd if=/dev/zero bs=1k count=1000 of=/dev/stderr; echo hello")
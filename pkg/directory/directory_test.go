@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"runtime"
 	"sort"
 	"testing"
 )
@@ -191,3 +192,38 @@ func TestSizeNonExistingDirectory(t *testing.T) {
 		t.Fatalf("error is expected")
 	}
 }
+
+// Usage of a directory with a nested directory and two hard links to a
+// 5-byte file should be 5 bytes and 3 inodes
+func TestUsageHardLinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hard links are not counted once on windows")
+	}
+	var dir string
+	var err error
+	if dir, err = ioutil.TempDir(os.TempDir(), "testUsageHardLinks"); err != nil {
+		t.Fatalf("failed to create directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err = os.Mkdir(filepath.Join(dir, "nested"), 0755); err != nil {
+		t.Fatalf("failed to create nested directory: %s", err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, "file"), []byte{97, 98, 99, 100, 101}, 0644); err != nil {
+		t.Fatalf("failed to create file: %s", err)
+	}
+	if err = os.Link(filepath.Join(dir, "file"), filepath.Join(dir, "nested", "link")); err != nil {
+		t.Fatalf("failed to create hard link: %s", err)
+	}
+
+	size, inodes, err := Usage(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("failed to get usage: %s", err)
+	}
+	if size != 5 {
+		t.Fatalf("directory with one 5-byte file has size: %d", size)
+	}
+	if inodes != 3 {
+		t.Fatalf("directory with a nested directory and one file has %d inodes", inodes)
+	}
+}
@@ -11,11 +11,19 @@ import (
 
 // Size walks a directory tree and returns its total size in bytes.
 func Size(ctx context.Context, dir string) (size int64, err error) {
+	size, _, err = Usage(ctx, dir)
+	return
+}
+
+// Usage walks a directory tree and returns its total size in bytes and the
+// number of inodes it uses, including directories. Hard links are counted
+// once.
+func Usage(ctx context.Context, dir string) (size int64, inodes int64, err error) {
 	data := make(map[uint64]struct{})
 	err = filepath.Walk(dir, func(d string, fileInfo os.FileInfo, err error) error {
 		if err != nil {
-			// if dir does not exist, Size() returns the error.
-			// if dir/x disappeared while walking, Size() ignores dir/x.
+			// if dir does not exist, Usage() returns the error.
+			// if dir/x disappeared while walking, Usage() ignores dir/x.
 			if os.IsNotExist(err) && d != dir {
 				return nil
 			}
@@ -27,26 +35,24 @@ func Size(ctx context.Context, dir string) (size int64, err error) {
 		default:
 		}
 
-		// Ignore directory sizes
 		if fileInfo == nil {
 			return nil
 		}
 
-		s := fileInfo.Size()
-		if fileInfo.IsDir() || s == 0 {
-			return nil
-		}
-
 		// Check inode to handle hard links correctly
-		inode := fileInfo.Sys().(*syscall.Stat_t).Ino
 		// inode is not a uint64 on all platforms. Cast it to avoid issues.
+		inode := uint64(fileInfo.Sys().(*syscall.Stat_t).Ino)
 		if _, exists := data[inode]; exists {
 			return nil
 		}
-		// inode is not a uint64 on all platforms. Cast it to avoid issues.
 		data[inode] = struct{}{}
+		inodes++
 
-		size += s
+		// Ignore directory sizes
+		if fileInfo.IsDir() {
+			return nil
+		}
+		size += fileInfo.Size()
 
 		return nil
 	})
@@ -8,10 +8,17 @@ import (
 
 // Size walks a directory tree and returns its total size in bytes.
 func Size(ctx context.Context, dir string) (size int64, err error) {
+	size, _, err = Usage(ctx, dir)
+	return
+}
+
+// Usage walks a directory tree and returns its total size in bytes and the
+// number of files and directories it holds, including dir.
+func Usage(ctx context.Context, dir string) (size int64, inodes int64, err error) {
 	err = filepath.Walk(dir, func(d string, fileInfo os.FileInfo, err error) error {
 		if err != nil {
-			// if dir does not exist, Size() returns the error.
-			// if dir/x disappeared while walking, Size() ignores dir/x.
+			// if dir does not exist, Usage() returns the error.
+			// if dir/x disappeared while walking, Usage() ignores dir/x.
 			if os.IsNotExist(err) && d != dir {
 				return nil
 			}
@@ -24,17 +31,16 @@ func Size(ctx context.Context, dir string) (size int64, err error) {
 		default:
 		}
 
-		// Ignore directory sizes
 		if fileInfo == nil {
 			return nil
 		}
+		inodes++
 
-		s := fileInfo.Size()
-		if fileInfo.IsDir() || s == 0 {
+		// Ignore directory sizes
+		if fileInfo.IsDir() {
 			return nil
 		}
-
-		size += s
+		size += fileInfo.Size()
 
 		return nil
 	})
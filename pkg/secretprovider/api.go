@@ -0,0 +1,32 @@
+// Package secretprovider implements the plugin side of secret provider
+// plugins, which the swarm managers use to fetch the values of the secrets
+// created with a driver (`SecretSpec.Driver`) when their tasks are
+// dispatched, rather than storing the values in the raft store.
+//
+// A secret provider plugin is a plugin implementing the "secretprovider"
+// capability, discovered like the volume and network plugins (managed v2
+// plugins with the "docker.secretprovider/1.0" interface type, or legacy
+// plugins listening on a socket in /run/docker/plugins). It serves the
+// endpoints of the handler returned by NewHandler.
+package secretprovider // import "github.com/ellcrys/docker/pkg/secretprovider"
+
+import (
+	"github.com/docker/swarmkit/manager/drivers"
+)
+
+const (
+	// SecretProviderAPIImplements is the name of the interface all secret
+	// provider plugins implement
+	SecretProviderAPIImplements = drivers.SecretsProviderCapability
+
+	// SecretProviderAPIGetSecret is the url for fetching the value of a
+	// secret
+	SecretProviderAPIGetSecret = drivers.SecretsProviderAPI
+)
+
+// Driver provides the values of secrets from a secret store.
+type Driver interface {
+	// GetSecret returns the value of the secret requested by a task of a
+	// service
+	GetSecret(req *drivers.SecretsProviderRequest) ([]byte, error)
+}
@@ -0,0 +1,116 @@
+package secretprovider // import "github.com/ellcrys/docker/pkg/secretprovider"
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ellcrys/docker/pkg/ioutils"
+	"github.com/docker/swarmkit/manager/drivers"
+	"github.com/pkg/errors"
+)
+
+// KeySize is the size of the keys of the secrets files, which are encrypted
+// with AES-256-GCM.
+const KeySize = 32
+
+// FileDriver is a Driver providing the secrets stored in a local file
+// encrypted with a key, as written by WriteFile. The file is read on each
+// request, so that the secrets can be updated without restarting the plugin.
+type FileDriver struct {
+	path string
+	aead cipher.AEAD
+}
+
+// NewFileDriver returns a driver providing the secrets of the file at path,
+// encrypted with key.
+func NewFileDriver(path string, key []byte) (*FileDriver, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &FileDriver{path: path, aead: aead}, nil
+}
+
+// GetSecret returns the value of the secret with the requested name.
+func (d *FileDriver) GetSecret(req *drivers.SecretsProviderRequest) ([]byte, error) {
+	secrets, err := readFile(d.path, d.aead)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := secrets[req.SecretName]
+	if !ok {
+		return nil, errors.Errorf("secret %s not found", req.SecretName)
+	}
+	return value, nil
+}
+
+// ReadFile returns the secrets stored in the file at path, encrypted with
+// key.
+func ReadFile(path string, key []byte) (map[string][]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return readFile(path, aead)
+}
+
+// WriteFile atomically writes the secrets to the file at path, encrypted with
+// key. The file is only readable by its owner.
+func WriteFile(path string, key []byte, secrets map[string][]byte) error {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return errors.Wrap(err, "error generating nonce")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(path, aead.Seal(nonce, nonce, b, nil), 0600)
+}
+
+func readFile(path string, aead cipher.AEAD) (map[string][]byte, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string][]byte{}, nil
+		}
+		return nil, errors.Wrap(err, "error reading secrets file")
+	}
+	if len(b) < aead.NonceSize() {
+		return nil, errors.New("invalid secrets file")
+	}
+	nonce, ciphertext := b[:aead.NonceSize()], b[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "error decrypting secrets file")
+	}
+	var secrets map[string][]byte
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, errors.Wrap(err, "error decoding secrets file")
+	}
+	return secrets, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, errors.Errorf("invalid key size %d, the key must be %d bytes", len(key), KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
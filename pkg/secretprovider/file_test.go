@@ -0,0 +1,44 @@
+package secretprovider // import "github.com/ellcrys/docker/pkg/secretprovider"
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/swarmkit/manager/drivers"
+	"github.com/gotestyourself/gotestyourself/assert"
+	is "github.com/gotestyourself/gotestyourself/assert/cmp"
+)
+
+func TestFileDriver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secretprovider")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "secrets")
+	key := bytes.Repeat([]byte{1}, KeySize)
+
+	d, err := NewFileDriver(path, key)
+	assert.NilError(t, err)
+
+	_, err = d.GetSecret(&drivers.SecretsProviderRequest{SecretName: "foo"})
+	assert.Check(t, is.ErrorContains(err, "secret foo not found"))
+
+	assert.NilError(t, WriteFile(path, key, map[string][]byte{"foo": []byte("bar")}))
+
+	b, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Check(t, !bytes.Contains(b, []byte("bar")), "secrets file is not encrypted")
+
+	value, err := d.GetSecret(&drivers.SecretsProviderRequest{SecretName: "foo"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("bar", string(value)))
+
+	_, err = ReadFile(path, bytes.Repeat([]byte{2}, KeySize))
+	assert.Check(t, is.ErrorContains(err, "error decrypting secrets file"))
+
+	_, err = NewFileDriver(path, []byte("short"))
+	assert.Check(t, is.ErrorContains(err, "invalid key size"))
+}
@@ -0,0 +1,38 @@
+package secretprovider // import "github.com/ellcrys/docker/pkg/secretprovider"
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ellcrys/docker/pkg/plugins"
+	"github.com/ellcrys/docker/pkg/plugins/transport"
+	"github.com/docker/swarmkit/manager/drivers"
+)
+
+// NewHandler returns the http handler of a secret provider plugin serving the
+// secrets of the driver d.
+func NewHandler(d Driver) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Plugin.Activate", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, &plugins.Manifest{Implements: []string{SecretProviderAPIImplements}})
+	})
+	mux.HandleFunc(SecretProviderAPIGetSecret, func(w http.ResponseWriter, r *http.Request) {
+		var req drivers.SecretsProviderRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, &drivers.SecretsProviderResponse{Err: "error decoding request: " + err.Error()})
+			return
+		}
+		value, err := d.GetSecret(&req)
+		if err != nil {
+			writeJSON(w, &drivers.SecretsProviderResponse{Err: err.Error()})
+			return
+		}
+		writeJSON(w, &drivers.SecretsProviderResponse{Value: value})
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", transport.VersionMimetype)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,55 @@
+package secretprovider // import "github.com/ellcrys/docker/pkg/secretprovider"
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ellcrys/docker/pkg/plugins"
+	"github.com/docker/go-connections/tlsconfig"
+	"github.com/docker/swarmkit/api"
+	"github.com/docker/swarmkit/manager/drivers"
+	"github.com/gotestyourself/gotestyourself/assert"
+	is "github.com/gotestyourself/gotestyourself/assert/cmp"
+	"github.com/pkg/errors"
+)
+
+type mapDriver map[string]string
+
+func (d mapDriver) GetSecret(req *drivers.SecretsProviderRequest) ([]byte, error) {
+	v, ok := d[req.SecretName+"/"+req.ServiceName]
+	if !ok {
+		return nil, errors.New("no such secret")
+	}
+	return []byte(v), nil
+}
+
+type testPlugin struct {
+	client *plugins.Client
+}
+
+func (p *testPlugin) Client() *plugins.Client       { return p.client }
+func (p *testPlugin) Name() string                  { return "test" }
+func (p *testPlugin) ScopedPath(path string) string { return path }
+func (p *testPlugin) IsV1() bool                    { return true }
+
+func TestHandler(t *testing.T) {
+	server := httptest.NewServer(NewHandler(mapDriver{"foo/web": "bar"}))
+	defer server.Close()
+
+	client, err := plugins.NewClient(server.URL, &tlsconfig.Options{InsecureSkipVerify: true})
+	assert.NilError(t, err)
+
+	var manifest plugins.Manifest
+	assert.NilError(t, client.Call("Plugin.Activate", nil, &manifest))
+	assert.Check(t, is.DeepEqual([]string{SecretProviderAPIImplements}, manifest.Implements))
+
+	d := drivers.NewSecretDriver(&testPlugin{client: client})
+	task := &api.Task{ServiceAnnotations: api.Annotations{Name: "web"}}
+
+	value, err := d.Get(&api.SecretSpec{Annotations: api.Annotations{Name: "foo"}}, task)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("bar", string(value)))
+
+	_, err = d.Get(&api.SecretSpec{Annotations: api.Annotations{Name: "baz"}}, task)
+	assert.Check(t, is.Error(err, "no such secret"))
+}
@@ -18,6 +18,8 @@ import (
 type ServiceOptions struct {
 	AllowNondistributableArtifacts []string `json:"allow-nondistributable-artifacts,omitempty"`
 	Mirrors                        []string `json:"registry-mirrors,omitempty"`
+	HostMirrors                    []string `json:"registry-host-mirrors,omitempty"`
+	Rewrites                       []string `json:"registry-rewrites,omitempty"`
 	InsecureRegistries             []string `json:"insecure-registries,omitempty"`
 
 	// V2Only controls access to legacy registries.  If it is set to true via the
@@ -29,6 +31,11 @@ type ServiceOptions struct {
 type serviceConfig struct {
 	registrytypes.ServiceConfig
 	V2Only bool
+	// HostMirrors are the mirrors of each registry, in order of preference.
+	HostMirrors map[string][]string
+	// Rewrites are the rules rewriting the references of the images pulled,
+	// the first matching rule is applied.
+	Rewrites []rewriteRule
 }
 
 var (
@@ -76,7 +83,8 @@ func newServiceConfig(options ServiceOptions) (*serviceConfig, error) {
 			// Hack: Bypass setting the mirrors to IndexConfigs since they are going away
 			// and Mirrors are only for the official registry anyways.
 		},
-		V2Only: options.V2Only,
+		V2Only:      options.V2Only,
+		HostMirrors: make(map[string][]string),
 	}
 	if err := config.LoadAllowNondistributableArtifacts(options.AllowNondistributableArtifacts); err != nil {
 		return nil, err
@@ -84,6 +92,12 @@ func newServiceConfig(options ServiceOptions) (*serviceConfig, error) {
 	if err := config.LoadMirrors(options.Mirrors); err != nil {
 		return nil, err
 	}
+	if err := config.LoadHostMirrors(options.HostMirrors); err != nil {
+		return nil, err
+	}
+	if err := config.LoadRewrites(options.Rewrites); err != nil {
+		return nil, err
+	}
 	if err := config.LoadInsecureRegistries(options.InsecureRegistries); err != nil {
 		return nil, err
 	}
@@ -150,7 +164,37 @@ func (config *serviceConfig) LoadMirrors(mirrors []string) error {
 	// Configure public registry since mirrors may have changed.
 	config.IndexConfigs[IndexName] = &registrytypes.IndexInfo{
 		Name:     IndexName,
-		Mirrors:  config.Mirrors,
+		Mirrors:  config.mirrors(IndexName),
+		Secure:   true,
+		Official: true,
+	}
+
+	return nil
+}
+
+// LoadHostMirrors loads the mirrors of each registry to config, after
+// removing duplicates. The mirrors are given as "registry=mirror", and the
+// mirrors of a registry are kept in the order they are given. Returns an
+// error if mirrors contains an invalid mirror.
+func (config *serviceConfig) LoadHostMirrors(mirrors []string) error {
+	hostMirrors := make(map[string][]string)
+	for _, mirror := range mirrors {
+		m, err := ValidateHostMirror(mirror)
+		if err != nil {
+			return err
+		}
+		parts := strings.SplitN(m, "=", 2)
+		host, url := parts[0], parts[1]
+		if !containsString(hostMirrors[host], url) {
+			hostMirrors[host] = append(hostMirrors[host], url)
+		}
+	}
+	config.HostMirrors = hostMirrors
+
+	// Configure public registry since mirrors may have changed.
+	config.IndexConfigs[IndexName] = &registrytypes.IndexInfo{
+		Name:     IndexName,
+		Mirrors:  config.mirrors(IndexName),
 		Secure:   true,
 		Official: true,
 	}
@@ -158,6 +202,30 @@ func (config *serviceConfig) LoadMirrors(mirrors []string) error {
 	return nil
 }
 
+// mirrors returns the mirrors of the registry, in order of preference. The
+// mirrors of the official registry set with --registry-mirror come first.
+func (config *serviceConfig) mirrors(indexName string) []string {
+	mirrors := make([]string, 0)
+	if indexName == IndexName {
+		mirrors = append(mirrors, config.Mirrors...)
+	}
+	for _, m := range config.HostMirrors[indexName] {
+		if !containsString(mirrors, m) {
+			mirrors = append(mirrors, m)
+		}
+	}
+	return mirrors
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // LoadInsecureRegistries loads insecure registries to config
 func (config *serviceConfig) LoadInsecureRegistries(registries []string) error {
 	// Localhost is by default considered as an insecure registry
@@ -230,7 +298,7 @@ skip:
 	// Configure public registry.
 	config.IndexConfigs[IndexName] = &registrytypes.IndexInfo{
 		Name:     IndexName,
-		Mirrors:  config.Mirrors,
+		Mirrors:  config.mirrors(IndexName),
 		Secure:   true,
 		Official: true,
 	}
@@ -333,6 +401,29 @@ func ValidateMirror(val string) (string, error) {
 	return strings.TrimSuffix(val, "/") + "/", nil
 }
 
+// ValidateHostMirror validates a "registry=mirror" mirror of a registry
+func ValidateHostMirror(val string) (string, error) {
+	parts := strings.SplitN(val, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", fmt.Errorf("invalid registry mirror %q: must be registry=mirror", val)
+	}
+	host, err := ValidateIndexName(parts[0])
+	if err != nil {
+		return "", err
+	}
+	if validateNoScheme(host) != nil {
+		return "", fmt.Errorf("invalid registry mirror %q: registry %s should not contain '://'", val, host)
+	}
+	if err := validateHostPort(host); err != nil {
+		return "", fmt.Errorf("invalid registry mirror %q: %v", val, err)
+	}
+	mirror, err := ValidateMirror(parts[1])
+	if err != nil {
+		return "", err
+	}
+	return host + "=" + mirror, nil
+}
+
 // ValidateIndexName validates an index name.
 func ValidateIndexName(val string) (string, error) {
 	// TODO: upstream this to check to reference package
@@ -387,13 +478,18 @@ func newIndexInfo(config *serviceConfig, indexName string) (*registrytypes.Index
 
 	// Return any configured index info, first.
 	if index, ok := config.IndexConfigs[indexName]; ok {
+		if !index.Official && len(config.HostMirrors[indexName]) > 0 {
+			withMirrors := *index
+			withMirrors.Mirrors = config.mirrors(indexName)
+			return &withMirrors, nil
+		}
 		return index, nil
 	}
 
 	// Construct a non-configured index info.
 	index := &registrytypes.IndexInfo{
 		Name:     indexName,
-		Mirrors:  make([]string, 0),
+		Mirrors:  config.mirrors(indexName),
 		Official: false,
 	}
 	index.Secure = isSecureIndex(config, indexName)
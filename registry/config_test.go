@@ -172,6 +172,58 @@ func TestValidateMirror(t *testing.T) {
 	}
 }
 
+func TestValidateHostMirror(t *testing.T) {
+	valid := map[string]string{
+		"registry.corp:5000=https://mirror.corp":   "registry.corp:5000=https://mirror.corp/",
+		"quay.io=http://127.0.0.1:5000/":           "quay.io=http://127.0.0.1:5000/",
+		"index.docker.io=https://mirror.corp:5000": "docker.io=https://mirror.corp:5000/",
+	}
+	for address, expected := range valid {
+		ret, err := ValidateHostMirror(address)
+		assert.Check(t, err, address)
+		assert.Check(t, is.Equal(ret, expected), address)
+	}
+
+	invalid := []string{
+		"https://mirror.corp",
+		"=https://mirror.corp",
+		"quay.io=",
+		"quay.io=mirror.corp",
+		"https://quay.io=https://mirror.corp",
+		"-quay.io=https://mirror.corp",
+		"quay.io=https://mirror.corp/v1/",
+	}
+	for _, address := range invalid {
+		_, err := ValidateHostMirror(address)
+		assert.Check(t, is.ErrorContains(err, ""), address)
+	}
+}
+
+func TestLoadHostMirrors(t *testing.T) {
+	config, err := newServiceConfig(ServiceOptions{
+		Mirrors: []string{"https://hub.mirror"},
+		HostMirrors: []string{
+			"quay.io=https://quay.mirror2",
+			"quay.io=https://quay.mirror1",
+			"quay.io=https://quay.mirror2",
+			"docker.io=https://hub.mirror",
+			"docker.io=https://hub.mirror2",
+		},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(config.mirrors("quay.io"), []string{"https://quay.mirror2/", "https://quay.mirror1/"}))
+	assert.Check(t, is.DeepEqual(config.mirrors(IndexName), []string{"https://hub.mirror/", "https://hub.mirror2/"}))
+	assert.Check(t, is.DeepEqual(config.IndexConfigs[IndexName].Mirrors, []string{"https://hub.mirror/", "https://hub.mirror2/"}))
+	assert.Check(t, is.Len(config.mirrors("registry.corp"), 0))
+
+	index, err := newIndexInfo(config, "quay.io")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(index.Mirrors, []string{"https://quay.mirror2/", "https://quay.mirror1/"}))
+
+	err = config.LoadHostMirrors([]string{"quay.io=ftp://quay.mirror"})
+	assert.Check(t, is.ErrorContains(err, "invalid mirror"))
+}
+
 func TestLoadInsecureRegistries(t *testing.T) {
 	testCases := []struct {
 		registries []string
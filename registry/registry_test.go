@@ -701,6 +701,50 @@ func TestMirrorEndpointLookup(t *testing.T) {
 	}
 }
 
+func TestHostMirrorEndpoints(t *testing.T) {
+	s, err := NewService(ServiceOptions{
+		HostMirrors: []string{"registry.corp:5000=https://mirror1.corp", "registry.corp:5000=http://mirror2.corp"},
+		V2Only:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pullAPIEndpoints, err := s.LookupPullEndpoints("registry.corp:5000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hosts []string
+	for _, pe := range pullAPIEndpoints {
+		hosts = append(hosts, pe.URL.String())
+	}
+	expected := []string{"https://mirror1.corp/", "http://mirror2.corp/", "https://registry.corp:5000"}
+	if strings.Join(hosts, " ") != strings.Join(expected, " ") {
+		t.Fatalf("Expected pull endpoints %v, got %v", expected, hosts)
+	}
+	if !pullAPIEndpoints[0].Mirror || !pullAPIEndpoints[1].Mirror || pullAPIEndpoints[2].Mirror {
+		t.Fatal("Expected the mirrors to be marked as mirrors")
+	}
+
+	pushAPIEndpoints, err := s.LookupPushEndpoints("registry.corp:5000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pushAPIEndpoints) != 1 || pushAPIEndpoints[0].URL.Host != "registry.corp:5000" {
+		t.Fatalf("Push endpoints should not contain mirrors, got %v", pushAPIEndpoints)
+	}
+
+	pullAPIEndpoints, err = s.LookupPullEndpoints("quay.io")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pe := range pullAPIEndpoints {
+		if pe.Mirror {
+			t.Fatalf("Expected no mirror for another registry, got %s", pe.URL)
+		}
+	}
+}
+
 func TestPushRegistryTag(t *testing.T) {
 	r := spawnTestRegistrySession(t)
 	repoRef, err := reference.ParseNormalizedNamed(REPO)
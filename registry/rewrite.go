@@ -0,0 +1,116 @@
+package registry // import "github.com/ellcrys/docker/registry"
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+)
+
+// rewriteRule rewrites the name of the repositories matching From to To. If
+// Prefix is set, the rule applies to all the repositories under From, as in
+// "docker.io/library/*=registry.corp/hub/*".
+type rewriteRule struct {
+	From   string
+	To     string
+	Prefix bool
+}
+
+// ValidateRewrite validates a "source=target" rewrite of repository names
+func ValidateRewrite(val string) (string, error) {
+	if _, err := parseRewriteRule(val); err != nil {
+		return "", err
+	}
+	return val, nil
+}
+
+func parseRewriteRule(val string) (rewriteRule, error) {
+	parts := strings.SplitN(val, "=", 2)
+	if len(parts) != 2 {
+		return rewriteRule{}, fmt.Errorf("invalid registry rewrite %q: must be source=target", val)
+	}
+	from, fromPrefix := trimWildcard(parts[0])
+	to, toPrefix := trimWildcard(parts[1])
+	if fromPrefix != toPrefix {
+		return rewriteRule{}, fmt.Errorf("invalid registry rewrite %q: either both or none of the source and target must end with /*", val)
+	}
+	for _, name := range []string{from, to} {
+		if err := validateRewriteName(name, fromPrefix); err != nil {
+			return rewriteRule{}, fmt.Errorf("invalid registry rewrite %q: %v", val, err)
+		}
+	}
+	return rewriteRule{From: from, To: to, Prefix: fromPrefix}, nil
+}
+
+func trimWildcard(name string) (string, bool) {
+	if strings.HasSuffix(name, "/*") {
+		return strings.TrimSuffix(name, "/*"), true
+	}
+	return name, false
+}
+
+// validateRewriteName checks that name is a fully qualified repository name,
+// or the prefix of one if prefix is set.
+func validateRewriteName(name string, prefix bool) error {
+	repo := name
+	if prefix {
+		// any repository under the prefix
+		repo = name + "/x"
+	}
+	named, err := reference.ParseNamed(repo)
+	if err != nil {
+		return fmt.Errorf("%s is not a fully qualified repository name: %v", name, err)
+	}
+	if !reference.IsNameOnly(named) {
+		return fmt.Errorf("%s must not have a tag or digest", name)
+	}
+	return nil
+}
+
+// LoadRewrites loads the rewrites of repository names to config. Returns an
+// error if rewrites contains an invalid rewrite.
+func (config *serviceConfig) LoadRewrites(rewrites []string) error {
+	var rules []rewriteRule
+	for _, r := range rewrites {
+		rule, err := parseRewriteRule(r)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, rule)
+	}
+	config.Rewrites = rules
+	return nil
+}
+
+// rewriteReference returns the reference rewritten by the first rule matching
+// its repository name, keeping its tag or digest. It returns false if no rule
+// matches.
+func (config *serviceConfig) rewriteReference(ref reference.Named) (reference.Named, bool, error) {
+	name := ref.Name()
+	for _, rule := range config.Rewrites {
+		var rewritten string
+		switch {
+		case rule.Prefix && strings.HasPrefix(name, rule.From+"/"):
+			rewritten = rule.To + strings.TrimPrefix(name, rule.From)
+		case !rule.Prefix && name == rule.From:
+			rewritten = rule.To
+		default:
+			continue
+		}
+
+		newRef, err := reference.ParseNamed(rewritten)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to rewrite %s to %s: %v", name, rewritten, err)
+		}
+		if canonical, ok := ref.(reference.Canonical); ok {
+			newRef, err = reference.WithDigest(newRef, canonical.Digest())
+		} else if tagged, ok := ref.(reference.Tagged); ok {
+			newRef, err = reference.WithTag(newRef, tagged.Tag())
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		return newRef, true, nil
+	}
+	return ref, false, nil
+}
@@ -0,0 +1,66 @@
+package registry // import "github.com/ellcrys/docker/registry"
+
+import (
+	"testing"
+
+	"github.com/docker/distribution/reference"
+	"github.com/gotestyourself/gotestyourself/assert"
+	is "github.com/gotestyourself/gotestyourself/assert/cmp"
+)
+
+func TestValidateRewrite(t *testing.T) {
+	valid := []string{
+		"docker.io/library/*=registry.corp/hub/*",
+		"quay.io/*=registry.corp:5000/quay/*",
+		"docker.io/library/alpine=registry.corp/base/alpine",
+	}
+	for _, rewrite := range valid {
+		_, err := ValidateRewrite(rewrite)
+		assert.Check(t, err, rewrite)
+	}
+
+	invalid := map[string]string{
+		"docker.io/library/*":                        "must be source=target",
+		"docker.io/library/*=registry.corp/hub":      "either both or none",
+		"library/*=registry.corp/hub/*":              "not a fully qualified repository name",
+		"docker.io/alpine=registry.corp/alpine":      "not a fully qualified repository name",
+		"docker.io/library/alpine:3=registry.corp/a": "must not have a tag or digest",
+		"docker.io/library/*=Registry.corp/HUB/*":    "not a fully qualified repository name",
+	}
+	for rewrite, expected := range invalid {
+		_, err := ValidateRewrite(rewrite)
+		assert.Check(t, is.ErrorContains(err, expected), rewrite)
+	}
+}
+
+func TestRewriteReference(t *testing.T) {
+	config, err := newServiceConfig(ServiceOptions{
+		Rewrites: []string{
+			"docker.io/library/alpine=registry.corp/base/alpine",
+			"docker.io/library/*=registry.corp/hub/*",
+			"quay.io/*=registry.corp:5000/quay/*",
+		},
+	})
+	assert.NilError(t, err)
+
+	testCases := []struct {
+		ref       string
+		expected  string
+		rewritten bool
+	}{
+		{ref: "alpine:3.7", expected: "registry.corp/base/alpine:3.7", rewritten: true},
+		{ref: "busybox", expected: "registry.corp/hub/busybox", rewritten: true},
+		{ref: "busybox@sha256:b5cf3f3a8a3b2da2ddb8e6b1b8bc6f0f9f8d0b1a8b7cb7d2c1a7fb8f6b4d4e21", expected: "registry.corp/hub/busybox@sha256:b5cf3f3a8a3b2da2ddb8e6b1b8bc6f0f9f8d0b1a8b7cb7d2c1a7fb8f6b4d4e21", rewritten: true},
+		{ref: "quay.io/coreos/etcd:v3", expected: "registry.corp:5000/quay/coreos/etcd:v3", rewritten: true},
+		{ref: "someuser/app:latest", expected: "docker.io/someuser/app:latest"},
+		{ref: "quay.io.example.com/app", expected: "quay.io.example.com/app"},
+	}
+	for _, tc := range testCases {
+		ref, err := reference.ParseNormalizedNamed(tc.ref)
+		assert.NilError(t, err)
+		rewritten, ok, err := config.rewriteReference(ref)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(ok, tc.rewritten), tc.ref)
+		assert.Check(t, is.Equal(rewritten.String(), tc.expected), tc.ref)
+	}
+}
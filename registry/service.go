@@ -28,11 +28,14 @@ type Service interface {
 	LookupPullEndpoints(hostname string) (endpoints []APIEndpoint, err error)
 	LookupPushEndpoints(hostname string) (endpoints []APIEndpoint, err error)
 	ResolveRepository(name reference.Named) (*RepositoryInfo, error)
+	RewriteReference(ref reference.Named) (reference.Named, bool, error)
 	Search(ctx context.Context, term string, limit int, authConfig *types.AuthConfig, userAgent string, headers map[string][]string) (*registrytypes.SearchResults, error)
 	ServiceConfig() *registrytypes.ServiceConfig
 	TLSConfig(hostname string) (*tls.Config, error)
 	LoadAllowNondistributableArtifacts([]string) error
 	LoadMirrors([]string) error
+	LoadHostMirrors([]string) error
+	LoadRewrites([]string) error
 	LoadInsecureRegistries([]string) error
 }
 
@@ -73,6 +76,11 @@ func (s *DefaultService) ServiceConfig() *registrytypes.ServiceConfig {
 	for key, value := range s.config.ServiceConfig.IndexConfigs {
 		servConfig.IndexConfigs[key] = value
 	}
+	for key := range s.config.HostMirrors {
+		if index, err := newIndexInfo(s.config, key); err == nil {
+			servConfig.IndexConfigs[index.Name] = index
+		}
+	}
 
 	servConfig.Mirrors = append(servConfig.Mirrors, s.config.ServiceConfig.Mirrors...)
 
@@ -95,6 +103,22 @@ func (s *DefaultService) LoadMirrors(mirrors []string) error {
 	return s.config.LoadMirrors(mirrors)
 }
 
+// LoadHostMirrors loads the mirrors of each registry for Service
+func (s *DefaultService) LoadHostMirrors(mirrors []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.config.LoadHostMirrors(mirrors)
+}
+
+// LoadRewrites loads the rewrites of repository names for Service
+func (s *DefaultService) LoadRewrites(rewrites []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.config.LoadRewrites(rewrites)
+}
+
 // LoadInsecureRegistries loads insecure registries for Service
 func (s *DefaultService) LoadInsecureRegistries(registries []string) error {
 	s.mu.Lock()
@@ -247,6 +271,15 @@ func (s *DefaultService) ResolveRepository(name reference.Named) (*RepositoryInf
 	return newRepositoryInfo(s.config, name)
 }
 
+// RewriteReference returns the reference of an image to pull, rewritten by
+// the first configured rewrite matching its repository name. It returns false
+// if the reference is not rewritten.
+func (s *DefaultService) RewriteReference(ref reference.Named) (reference.Named, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.config.rewriteReference(ref)
+}
+
 // APIEndpoint represents a remote API endpoint
 type APIEndpoint struct {
 	Mirror                         bool
@@ -282,7 +315,8 @@ func (s *DefaultService) tlsConfigForMirror(mirrorURL *url.URL) (*tls.Config, er
 
 // LookupPullEndpoints creates a list of endpoints to try to pull from, in order of preference.
 // It gives preference to v2 endpoints over v1, mirrors over the actual
-// registry, and HTTPS over plain HTTP.
+// registry, and HTTPS over plain HTTP. The mirrors are tried in the order
+// they are configured.
 func (s *DefaultService) LookupPullEndpoints(hostname string) (endpoints []APIEndpoint, err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -11,26 +11,9 @@ func (s *DefaultService) lookupV2Endpoints(hostname string) (endpoints []APIEndp
 	tlsConfig := tlsconfig.ServerDefault()
 	if hostname == DefaultNamespace || hostname == IndexHostname {
 		// v2 mirrors
-		for _, mirror := range s.config.Mirrors {
-			if !strings.HasPrefix(mirror, "http://") && !strings.HasPrefix(mirror, "https://") {
-				mirror = "https://" + mirror
-			}
-			mirrorURL, err := url.Parse(mirror)
-			if err != nil {
-				return nil, err
-			}
-			mirrorTLSConfig, err := s.tlsConfigForMirror(mirrorURL)
-			if err != nil {
-				return nil, err
-			}
-			endpoints = append(endpoints, APIEndpoint{
-				URL: mirrorURL,
-				// guess mirrors are v2
-				Version:      APIVersion2,
-				Mirror:       true,
-				TrimHostname: true,
-				TLSConfig:    mirrorTLSConfig,
-			})
+		endpoints, err = s.lookupV2MirrorEndpoints(IndexName)
+		if err != nil {
+			return nil, err
 		}
 		// v2 registry
 		endpoints = append(endpoints, APIEndpoint{
@@ -51,7 +34,13 @@ func (s *DefaultService) lookupV2Endpoints(hostname string) (endpoints []APIEndp
 		return nil, err
 	}
 
-	endpoints = []APIEndpoint{
+	// v2 mirrors
+	endpoints, err = s.lookupV2MirrorEndpoints(hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints = append(endpoints, []APIEndpoint{
 		{
 			URL: &url.URL{
 				Scheme: "https",
@@ -62,7 +51,7 @@ func (s *DefaultService) lookupV2Endpoints(hostname string) (endpoints []APIEndp
 			TrimHostname:                   true,
 			TLSConfig:                      tlsConfig,
 		},
-	}
+	}...)
 
 	if tlsConfig.InsecureSkipVerify {
 		endpoints = append(endpoints, APIEndpoint{
@@ -80,3 +69,30 @@ func (s *DefaultService) lookupV2Endpoints(hostname string) (endpoints []APIEndp
 
 	return endpoints, nil
 }
+
+// lookupV2MirrorEndpoints returns the endpoints of the mirrors of the
+// registry, in order of preference.
+func (s *DefaultService) lookupV2MirrorEndpoints(indexName string) (endpoints []APIEndpoint, err error) {
+	for _, mirror := range s.config.mirrors(indexName) {
+		if !strings.HasPrefix(mirror, "http://") && !strings.HasPrefix(mirror, "https://") {
+			mirror = "https://" + mirror
+		}
+		mirrorURL, err := url.Parse(mirror)
+		if err != nil {
+			return nil, err
+		}
+		mirrorTLSConfig, err := s.tlsConfigForMirror(mirrorURL)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, APIEndpoint{
+			URL: mirrorURL,
+			// guess mirrors are v2
+			Version:      APIVersion2,
+			Mirror:       true,
+			TrimHostname: true,
+			TLSConfig:    mirrorTLSConfig,
+		})
+	}
+	return endpoints, nil
+}
@@ -10,9 +10,10 @@ import (
 )
 
 const (
-	backoffMultiplier = 2
-	defaultTimeout    = 100 * time.Millisecond
-	maxRestartTimeout = 1 * time.Minute
+	backoffMultiplier  = 2
+	defaultTimeout     = 100 * time.Millisecond
+	maxRestartTimeout  = 1 * time.Minute
+	defaultResetWindow = 10 * time.Second
 )
 
 // ErrRestartCanceled is returned when the restart manager has been
@@ -66,19 +67,23 @@ func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped
 	if rm.active {
 		return false, nil, fmt.Errorf("invalid call on an active restart manager")
 	}
-	// if the container ran for more than 10s, regardless of status and policy reset the
-	// the timeout back to the default.
-	if executionDuration.Seconds() >= 10 {
+	initialTimeout := durationWithDefault(rm.policy.InitialBackoff, defaultTimeout)
+	maxTimeout := durationWithDefault(rm.policy.MaxBackoff, maxRestartTimeout)
+	resetWindow := durationWithDefault(rm.policy.BackoffResetWindow, defaultResetWindow)
+
+	// if the container ran for longer than the reset window, regardless of status and
+	// policy reset the timeout back to the initial one.
+	if executionDuration >= resetWindow {
 		rm.timeout = 0
 	}
 	switch {
 	case rm.timeout == 0:
-		rm.timeout = defaultTimeout
-	case rm.timeout < maxRestartTimeout:
+		rm.timeout = initialTimeout
+	case rm.timeout < maxTimeout:
 		rm.timeout *= backoffMultiplier
 	}
-	if rm.timeout > maxRestartTimeout {
-		rm.timeout = maxRestartTimeout
+	if rm.timeout > maxTimeout {
+		rm.timeout = maxTimeout
 	}
 
 	var restart bool
@@ -87,8 +92,9 @@ func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped
 		restart = true
 	case rm.policy.IsUnlessStopped() && !hasBeenManuallyStopped:
 		restart = true
-	case rm.policy.IsOnFailure():
-		// the default value of 0 for MaximumRetryCount means that we will not enforce a maximum count
+	case rm.policy.IsOnFailure(), rm.policy.IsOnUnhealthy():
+		// the default value of 0 for MaximumRetryCount means that we will not enforce a maximum count.
+		// Containers found unhealthy are killed, so they restart with a non-zero exit code as well.
 		if max := rm.policy.MaximumRetryCount; max == 0 || rm.restartCount < max {
 			restart = exitCode != 0
 		}
@@ -131,3 +137,11 @@ func (rm *restartManager) Cancel() error {
 	})
 	return nil
 }
+
+// durationWithDefault returns defaultValue if configuredValue is zero.
+func durationWithDefault(configuredValue, defaultValue time.Duration) time.Duration {
+	if configuredValue == 0 {
+		return defaultValue
+	}
+	return configuredValue
+}
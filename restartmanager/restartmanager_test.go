@@ -34,3 +34,59 @@ func TestRestartManagerTimeoutReset(t *testing.T) {
 		t.Fatalf("restart manager should have a timeout of 100 ms but has %s", rm.timeout)
 	}
 }
+
+func TestRestartManagerConfiguredBackoff(t *testing.T) {
+	policy := container.RestartPolicy{
+		Name:               "always",
+		InitialBackoff:     time.Second,
+		MaxBackoff:         3 * time.Second,
+		BackoffResetWindow: time.Minute,
+	}
+	rm := New(policy, 0).(*restartManager)
+
+	for _, expected := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		rm.active = false
+		if _, _, err := rm.ShouldRestart(1, false, 30*time.Second); err != nil {
+			t.Fatal(err)
+		}
+		if rm.timeout != expected {
+			t.Fatalf("restart manager should have a timeout of %s but has %s", expected, rm.timeout)
+		}
+	}
+
+	rm.active = false
+	if _, _, err := rm.ShouldRestart(1, false, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if rm.timeout != time.Second {
+		t.Fatalf("restart manager should have a timeout of 1s but has %s", rm.timeout)
+	}
+}
+
+func TestRestartManagerOnUnhealthy(t *testing.T) {
+	rm := New(container.RestartPolicy{Name: "on-unhealthy", MaximumRetryCount: 1}, 0).(*restartManager)
+	should, _, err := rm.ShouldRestart(0, false, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if should {
+		t.Fatal("container should not be restarted after a successful exit")
+	}
+
+	should, _, err = rm.ShouldRestart(137, false, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !should {
+		t.Fatal("container should be restarted after being killed")
+	}
+
+	rm.active = false
+	should, _, err = rm.ShouldRestart(137, false, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if should {
+		t.Fatal("container should not be restarted past the maximum retry count")
+	}
+}
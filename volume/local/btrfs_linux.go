@@ -0,0 +1,118 @@
+package local // import "github.com/ellcrys/docker/volume/local"
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	btrfsSuperMagic        = 0x9123683E
+	btrfsFirstFreeObjectID = 256
+
+	// ioctls from linux/btrfs.h, _IOW(BTRFS_IOCTL_MAGIC, nr, 4096 bytes)
+	btrfsIocSubvolCreate = 0x5000940E
+	btrfsIocSnapDestroy  = 0x5000940F
+	btrfsIocSnapCreateV2 = 0x50009417
+
+	btrfsPathNameMax   = 4087
+	btrfsSubvolNameMax = 4039
+)
+
+// btrfsVolArgs is struct btrfs_ioctl_vol_args
+type btrfsVolArgs struct {
+	fd   int64
+	name [btrfsPathNameMax + 1]byte
+}
+
+// btrfsVolArgsV2 is struct btrfs_ioctl_vol_args_v2
+type btrfsVolArgsV2 struct {
+	fd      int64
+	transid uint64
+	flags   uint64
+	unused  [4]uint64
+	name    [btrfsSubvolNameMax + 1]byte
+}
+
+// isBtrfs returns true if path is on a btrfs filesystem.
+func isBtrfs(path string) bool {
+	var buf unix.Statfs_t
+	if err := unix.Statfs(path, &buf); err != nil {
+		return false
+	}
+	return uint32(buf.Type) == btrfsSuperMagic
+}
+
+// isSubvolume returns true if path is the root of a btrfs subvolume.
+func isSubvolume(path string) bool {
+	fi, err := os.Lstat(path)
+	if err != nil || !fi.IsDir() {
+		return false
+	}
+	if stat, ok := fi.Sys().(*syscall.Stat_t); !ok || stat.Ino != btrfsFirstFreeObjectID {
+		return false
+	}
+	return isBtrfs(path)
+}
+
+func btrfsIoctl(dir string, req uintptr, args unsafe.Pointer) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), req, uintptr(args)); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// createSubvolume creates the btrfs subvolume path.
+func createSubvolume(path string) error {
+	var args btrfsVolArgs
+	name := filepath.Base(path)
+	if len(name) > btrfsPathNameMax {
+		return errors.Errorf("subvolume name too long: %s", name)
+	}
+	copy(args.name[:], name)
+	if err := btrfsIoctl(filepath.Dir(path), btrfsIocSubvolCreate, unsafe.Pointer(&args)); err != nil {
+		return errors.Wrapf(err, "failed to create btrfs subvolume %s", path)
+	}
+	return nil
+}
+
+// snapshotSubvolume creates the btrfs subvolume dst as a snapshot of the
+// subvolume src.
+func snapshotSubvolume(src, dst string) error {
+	srcDir, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer srcDir.Close()
+
+	var args btrfsVolArgsV2
+	name := filepath.Base(dst)
+	if len(name) > btrfsSubvolNameMax {
+		return errors.Errorf("subvolume name too long: %s", name)
+	}
+	args.fd = int64(srcDir.Fd())
+	copy(args.name[:], name)
+	if err := btrfsIoctl(filepath.Dir(dst), btrfsIocSnapCreateV2, unsafe.Pointer(&args)); err != nil {
+		return errors.Wrapf(err, "failed to snapshot btrfs subvolume %s", src)
+	}
+	return nil
+}
+
+// destroySubvolume removes the btrfs subvolume path and its content.
+func destroySubvolume(path string) error {
+	var args btrfsVolArgs
+	copy(args.name[:], filepath.Base(path))
+	if err := btrfsIoctl(filepath.Dir(path), btrfsIocSnapDestroy, unsafe.Pointer(&args)); err != nil {
+		return errors.Wrapf(err, "failed to destroy btrfs subvolume %s", path)
+	}
+	return nil
+}
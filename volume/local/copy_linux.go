@@ -0,0 +1,54 @@
+package local // import "github.com/ellcrys/docker/volume/local"
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ellcrys/docker/daemon/graphdriver/copy"
+	"github.com/ellcrys/docker/pkg/idtools"
+	"github.com/sirupsen/logrus"
+)
+
+// createDataPath creates the data directory of a volume. On btrfs, the data
+// directory is a subvolume, so that the volume can be snapshotted.
+func createDataPath(path string, rootIDs idtools.IDPair) error {
+	parent := filepath.Dir(path)
+	if err := idtools.MkdirAllAndChown(parent, 0755, rootIDs); err != nil {
+		return err
+	}
+	if isBtrfs(parent) {
+		err := createSubvolume(path)
+		if err == nil {
+			return os.Chown(path, rootIDs.UID, rootIDs.GID)
+		}
+		logrus.WithError(err).Debug("failed to create the volume as a btrfs subvolume, falling back to a directory")
+	}
+	return idtools.MkdirAllAndChown(path, 0755, rootIDs)
+}
+
+// removeDataPath removes the data directory of a volume.
+func removeDataPath(path string) error {
+	if isSubvolume(path) {
+		err := destroySubvolume(path)
+		if err == nil {
+			return nil
+		}
+		logrus.WithError(err).Debug("failed to destroy the volume btrfs subvolume, falling back to removing its content")
+	}
+	return removePath(path)
+}
+
+// copyData copies the data of a volume from src to the new data directory
+// dst. If src is a btrfs subvolume, dst is a snapshot of it. Otherwise the
+// files are copied, sharing their data with reflinks if the filesystem
+// supports them.
+func copyData(src, dst string) error {
+	if isSubvolume(src) {
+		err := snapshotSubvolume(src, dst)
+		if err == nil {
+			return nil
+		}
+		logrus.WithError(err).Debug("failed to snapshot the volume btrfs subvolume, falling back to copy")
+	}
+	return copy.DirCopy(src, dst, copy.Content, true)
+}
@@ -0,0 +1,21 @@
+// +build !linux
+
+package local // import "github.com/ellcrys/docker/volume/local"
+
+import (
+	"github.com/ellcrys/docker/errdefs"
+	"github.com/ellcrys/docker/pkg/idtools"
+	"github.com/pkg/errors"
+)
+
+func createDataPath(path string, rootIDs idtools.IDPair) error {
+	return idtools.MkdirAllAndChown(path, 0755, rootIDs)
+}
+
+func removeDataPath(path string) error {
+	return removePath(path)
+}
+
+func copyData(src, dst string) error {
+	return errdefs.NotImplemented(errors.New("volume snapshots are not supported on this platform"))
+}
@@ -71,12 +71,27 @@ func New(scope string, rootIDs idtools.IDPair) (*Root, error) {
 		}
 
 		name := filepath.Base(d.Name())
+		if strings.HasPrefix(name, ".") {
+			// remove the copies of volumes interrupted by a daemon restart
+			if err := removeDataPath(filepath.Join(rootDirectory, name, VolumeDataPathName)); err == nil {
+				removePath(filepath.Join(rootDirectory, name))
+			}
+			continue
+		}
 		v := &localVolume{
+			root:       r,
 			driverName: r.Name(),
 			name:       name,
 			path:       r.DataPath(name),
 		}
 		r.volumes[name] = v
+		if b, err := ioutil.ReadFile(filepath.Join(rootDirectory, name, snapshotFileName)); err == nil {
+			var snapshot snapshotConfig
+			if err := json.Unmarshal(b, &snapshot); err != nil {
+				return nil, errors.Wrapf(err, "error while unmarshaling snapshot information for volume: %s", name)
+			}
+			v.snapshotOf = snapshot.SnapshotOf
+		}
 		optsFilePath := filepath.Join(rootDirectory, name, "opts.json")
 		if b, err := ioutil.ReadFile(optsFilePath); err == nil {
 			opts := optsConfig{}
@@ -106,6 +121,7 @@ type Root struct {
 	path    string
 	volumes map[string]*localVolume
 	rootIDs idtools.IDPair
+	quota   quotaCtl
 }
 
 // List lists all the volumes
@@ -146,27 +162,38 @@ func (r *Root) Create(name string, opts map[string]string) (volume.Volume, error
 	}
 
 	path := r.DataPath(name)
-	if err := idtools.MkdirAllAndChown(path, 0755, r.rootIDs); err != nil {
+	v = &localVolume{
+		root:       r,
+		driverName: r.Name(),
+		name:       name,
+		path:       path,
+	}
+	if err := setOpts(v, opts); err != nil {
+		return nil, err
+	}
+
+	if err := idtools.MkdirAllAndChown(filepath.Dir(path), 0755, r.rootIDs); err != nil {
 		return nil, errors.Wrapf(errdefs.System(err), "error while creating volume path '%s'", path)
 	}
 
 	var err error
 	defer func() {
 		if err != nil {
+			removeDataPath(path)
 			os.RemoveAll(filepath.Dir(path))
 		}
 	}()
 
-	v = &localVolume{
-		driverName: r.Name(),
-		name:       name,
-		path:       path,
+	// the quota is set before the data directory is created, so that the
+	// data directory inherits it
+	if err = v.setQuota(); err != nil {
+		return nil, err
+	}
+	if err = createDataPath(path, r.rootIDs); err != nil {
+		return nil, errors.Wrapf(errdefs.System(err), "error while creating volume path '%s'", path)
 	}
 
 	if len(opts) != 0 {
-		if err = setOpts(v, opts); err != nil {
-			return nil, err
-		}
 		var b []byte
 		b, err = json.Marshal(v.opts)
 		if err != nil {
@@ -214,7 +241,7 @@ func (r *Root) Remove(v volume.Volume) error {
 		return errdefs.System(errors.Errorf("Unable to remove a directory outside of the local volume root %s: %s", r.scope, realPath))
 	}
 
-	if err := removePath(realPath); err != nil {
+	if err := removeDataPath(realPath); err != nil {
 		return err
 	}
 
@@ -270,6 +297,8 @@ func (r *Root) validateName(name string) error {
 // represents the volumes created by Root.
 type localVolume struct {
 	m sync.Mutex
+	// root is the driver that created the volume
+	root *Root
 	// unique name of the volume
 	name string
 	// path is the path on the host where the data lives
@@ -280,6 +309,8 @@ type localVolume struct {
 	opts *optsConfig
 	// active refcounts the active mounts
 	active activeMount
+	// snapshotOf is the name of the volume the volume is a snapshot of
+	snapshotOf string
 }
 
 // Name returns the name of the given Volume.
@@ -307,7 +338,7 @@ func (v *localVolume) CachedPath() string {
 func (v *localVolume) Mount(id string) (string, error) {
 	v.m.Lock()
 	defer v.m.Unlock()
-	if v.opts != nil {
+	if v.needsMount() {
 		if !v.active.mounted {
 			if err := v.mount(); err != nil {
 				return "", errdefs.System(err)
@@ -329,7 +360,7 @@ func (v *localVolume) Unmount(id string) error {
 	// Essentially docker doesn't care if this fails, it will send an error, but
 	// ultimately there's nothing that can be done. If we don't decrement the count
 	// this volume can never be removed until a daemon restart occurs.
-	if v.opts != nil {
+	if v.needsMount() {
 		v.active.count--
 	}
 
@@ -341,7 +372,7 @@ func (v *localVolume) Unmount(id string) error {
 }
 
 func (v *localVolume) unmount() error {
-	if v.opts != nil {
+	if v.needsMount() {
 		if err := mount.Unmount(v.path); err != nil {
 			if mounted, mErr := mount.Mounted(v.path); mounted || mErr != nil {
 				return errdefs.System(errors.Wrapf(err, "error while unmounting volume path '%s'", v.path))
@@ -362,6 +393,9 @@ func validateOpts(opts map[string]string) error {
 }
 
 func (v *localVolume) Status() map[string]interface{} {
+	if v.snapshotOf != "" {
+		return map[string]interface{}{"SnapshotOf": v.snapshotOf}
+	}
 	return nil
 }
 
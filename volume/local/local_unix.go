@@ -16,6 +16,8 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/docker/go-units"
+	"github.com/ellcrys/docker/errdefs"
 	"github.com/ellcrys/docker/pkg/mount"
 )
 
@@ -26,6 +28,7 @@ var (
 		"type":   true, // specify the filesystem type for mount, e.g. nfs
 		"o":      true, // generic mount options
 		"device": true, // device to mount from
+		"size":   true, // limit of the disk space used by the volume
 	}
 )
 
@@ -33,10 +36,11 @@ type optsConfig struct {
 	MountType   string
 	MountOpts   string
 	MountDevice string
+	Size        uint64
 }
 
 func (o *optsConfig) String() string {
-	return fmt.Sprintf("type='%s' device='%s' o='%s'", o.MountType, o.MountDevice, o.MountOpts)
+	return fmt.Sprintf("type='%s' device='%s' o='%s' size='%d'", o.MountType, o.MountDevice, o.MountOpts, o.Size)
 }
 
 // scopedPath verifies that the path where the volume is located
@@ -68,9 +72,51 @@ func setOpts(v *localVolume, opts map[string]string) error {
 		MountOpts:   opts["o"],
 		MountDevice: opts["device"],
 	}
+	if val, ok := opts["size"]; ok {
+		size, err := units.RAMInBytes(val)
+		if err != nil {
+			return validationError(fmt.Sprintf("invalid size option %q: %v", val, err))
+		}
+		if size <= 0 {
+			return validationError(fmt.Sprintf("invalid size option %q: the size must be positive", val))
+		}
+		if v.needsMount() {
+			return validationError("the size option cannot be combined with the type, o and device options")
+		}
+		v.opts.Size = uint64(size)
+	}
 	return nil
 }
 
+// needsMount returns true if the volume was created with options to mount a
+// filesystem at its path.
+func (v *localVolume) needsMount() bool {
+	return v.opts != nil && (v.opts.MountType != "" || v.opts.MountOpts != "" || v.opts.MountDevice != "")
+}
+
+// setQuota limits the disk space used by the volume to the size option it
+// was created with, if any.
+func (v *localVolume) setQuota() error {
+	if v.opts == nil || v.opts.Size == 0 {
+		return nil
+	}
+	return v.root.setQuota(filepath.Dir(v.path), v.opts.Size)
+}
+
+// Usage returns the disk space used by the volume and the limit set by the
+// size option it was created with. An error is returned if the volume was
+// created without size.
+func (v *localVolume) Usage() (int64, int64, error) {
+	if v.opts == nil || v.opts.Size == 0 {
+		return 0, 0, errdefs.NotImplemented(errors.New("volume was created without size"))
+	}
+	used, err := v.root.quotaUsage(filepath.Dir(v.path))
+	if err != nil {
+		return 0, 0, err
+	}
+	return int64(used), int64(v.opts.Size), nil
+}
+
 func (v *localVolume) mount() error {
 	if v.opts.MountDevice == "" {
 		return fmt.Errorf("missing device in volume options")
@@ -32,6 +32,14 @@ func setOpts(v *localVolume, opts map[string]string) error {
 	return nil
 }
 
+func (v *localVolume) needsMount() bool {
+	return false
+}
+
+func (v *localVolume) setQuota() error {
+	return nil
+}
+
 func (v *localVolume) mount() error {
 	return nil
 }
@@ -0,0 +1,51 @@
+package local // import "github.com/ellcrys/docker/volume/local"
+
+import (
+	"sync"
+
+	"github.com/ellcrys/docker/daemon/graphdriver/quota"
+	"github.com/ellcrys/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+// quotaCtl holds the project quota control of the volumes root, initialized
+// on first use so that the root is only required to support project quotas
+// when volumes are created with a size.
+type quotaCtl struct {
+	once sync.Once
+	ctl  *quota.Control
+	err  error
+}
+
+func (r *Root) quotaControl() (*quota.Control, error) {
+	r.quota.once.Do(func() {
+		r.quota.ctl, r.quota.err = quota.NewControl(r.path)
+	})
+	if r.quota.err != nil {
+		return nil, errdefs.InvalidParameter(errors.Wrap(r.quota.err, "the size option is only supported for local volumes on xfs with the 'pquota' mount option"))
+	}
+	return r.quota.ctl, nil
+}
+
+// setQuota limits the disk space used by the directory path, which must be
+// a direct child of the volumes root, to size bytes.
+func (r *Root) setQuota(path string, size uint64) error {
+	ctl, err := r.quotaControl()
+	if err != nil {
+		return err
+	}
+	if err := ctl.SetQuota(path, quota.Quota{Size: size}); err != nil {
+		return errdefs.System(err)
+	}
+	return nil
+}
+
+// quotaUsage returns the disk space used by the directory path, which must
+// have been limited with setQuota.
+func (r *Root) quotaUsage(path string) (uint64, error) {
+	ctl, err := r.quotaControl()
+	if err != nil {
+		return 0, err
+	}
+	return ctl.GetUsage(path)
+}
@@ -0,0 +1,84 @@
+package local // import "github.com/ellcrys/docker/volume/local"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ellcrys/docker/errdefs"
+	"github.com/ellcrys/docker/pkg/idtools"
+)
+
+func TestCreateWithSize(t *testing.T) {
+	rootDir, err := ioutil.TempDir("", "local-volume-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootDir)
+
+	r, err := New(rootDir, idtools.IDPair{UID: os.Getuid(), GID: os.Getegid()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, opts := range []map[string]string{
+		{"size": "notasize"},
+		{"size": "0"},
+		{"size": "10m", "type": "tmpfs", "device": "tmpfs"},
+	} {
+		if _, err := r.Create("test", opts); !errdefs.IsInvalidParameter(err) {
+			t.Fatalf("expected invalid parameter error for options %v, got: %v", opts, err)
+		}
+	}
+
+	v, err := r.Create("test", map[string]string{"size": "10m"})
+	if err != nil {
+		// the volumes root is not on xfs with project quotas enabled
+		if !errdefs.IsInvalidParameter(err) {
+			t.Fatalf("expected invalid parameter error, got: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(r.path, "test")); !os.IsNotExist(err) {
+			t.Fatalf("expected volume directory to be removed, got: %v", err)
+		}
+		if _, err := r.Get("test"); err == nil {
+			t.Fatal("expected volume not to be created")
+		}
+		return
+	}
+
+	_, limit, err := v.(*localVolume).Usage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limit != 10*1024*1024 {
+		t.Fatalf("expected limit of 10m, got %d", limit)
+	}
+	if _, err := v.Mount("1234"); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Unmount("1234"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateWithoutSizeUsage(t *testing.T) {
+	rootDir, err := ioutil.TempDir("", "local-volume-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootDir)
+
+	r, err := New(rootDir, idtools.IDPair{UID: os.Getuid(), GID: os.Getegid()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := r.Create("test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := v.(*localVolume).Usage(); !errdefs.IsNotImplemented(err) {
+		t.Fatalf("expected not implemented error, got: %v", err)
+	}
+}
@@ -0,0 +1,18 @@
+// +build !linux
+
+package local // import "github.com/ellcrys/docker/volume/local"
+
+import (
+	"github.com/ellcrys/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+type quotaCtl struct{}
+
+func (r *Root) setQuota(path string, size uint64) error {
+	return errdefs.InvalidParameter(errors.New("the size option is not supported for local volumes on this platform"))
+}
+
+func (r *Root) quotaUsage(path string) (uint64, error) {
+	return 0, errdefs.NotImplemented(errors.New("volume quotas are not supported on this platform"))
+}
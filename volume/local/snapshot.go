@@ -0,0 +1,101 @@
+package local // import "github.com/ellcrys/docker/volume/local"
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ellcrys/docker/errdefs"
+	"github.com/ellcrys/docker/volume"
+	"github.com/pkg/errors"
+)
+
+// snapshotFileName is the name of the file recording the volume a snapshot
+// was taken of, next to the data of the snapshot.
+const snapshotFileName = "snapshot.json"
+
+type snapshotConfig struct {
+	SnapshotOf string
+}
+
+// Snapshot creates a new volume with the given name, holding a copy of the
+// data of the volume, recorded as a snapshot of it.
+func (v *localVolume) Snapshot(name string) (volume.Volume, error) {
+	return v.root.copyVolume(v, name, true)
+}
+
+// Clone creates a new volume with the given name, holding a copy of the data
+// of the volume.
+func (v *localVolume) Clone(name string) (volume.Volume, error) {
+	return v.root.copyVolume(v, name, false)
+}
+
+// copyVolume creates a new volume with the given name from a copy of the
+// data of src. The data is copied to a temporary directory of the volumes
+// root, renamed to the volume directory once complete, so that the driver
+// is not locked during the copy.
+func (r *Root) copyVolume(src *localVolume, name string, snapshot bool) (volume.Volume, error) {
+	if err := r.validateName(name); err != nil {
+		return nil, err
+	}
+	if _, err := r.Get(name); err == nil {
+		return nil, errdefs.Conflict(errors.Errorf("volume %s already exists", name))
+	}
+
+	mountID := "copy-" + name
+	srcPath, err := src.Mount(mountID)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Unmount(mountID)
+
+	tmp, err := ioutil.TempDir(r.path, "."+name+"-")
+	if err != nil {
+		return nil, errdefs.System(errors.Wrap(err, "error while creating volume path"))
+	}
+	defer func() {
+		if tmp != "" {
+			removeDataPath(filepath.Join(tmp, VolumeDataPathName))
+			os.RemoveAll(tmp)
+		}
+	}()
+	if err := os.Chmod(tmp, 0755); err != nil {
+		return nil, errdefs.System(err)
+	}
+	if err := os.Chown(tmp, r.rootIDs.UID, r.rootIDs.GID); err != nil {
+		return nil, errdefs.System(err)
+	}
+	if err := copyData(srcPath, filepath.Join(tmp, VolumeDataPathName)); err != nil {
+		return nil, errors.Wrapf(err, "error while copying volume %s", src.name)
+	}
+
+	v := &localVolume{
+		root:       r,
+		driverName: r.Name(),
+		name:       name,
+		path:       r.DataPath(name),
+	}
+	if snapshot {
+		v.snapshotOf = src.name
+		b, err := json.Marshal(snapshotConfig{SnapshotOf: src.name})
+		if err != nil {
+			return nil, err
+		}
+		if err := ioutil.WriteFile(filepath.Join(tmp, snapshotFileName), b, 0600); err != nil {
+			return nil, errdefs.System(errors.Wrap(err, "error while persisting snapshot information"))
+		}
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	if _, exists := r.volumes[name]; exists {
+		return nil, errdefs.Conflict(errors.Errorf("volume %s already exists", name))
+	}
+	if err := os.Rename(tmp, filepath.Dir(v.path)); err != nil {
+		return nil, errdefs.System(errors.Wrap(err, "error while creating volume path"))
+	}
+	tmp = ""
+	r.volumes[name] = v
+	return v, nil
+}
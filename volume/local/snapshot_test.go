@@ -0,0 +1,91 @@
+// +build linux
+
+package local // import "github.com/ellcrys/docker/volume/local"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ellcrys/docker/errdefs"
+	"github.com/ellcrys/docker/pkg/idtools"
+	"github.com/ellcrys/docker/volume"
+)
+
+func TestSnapshotAndClone(t *testing.T) {
+	rootDir, err := ioutil.TempDir("", "local-volume-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootDir)
+
+	rootIDs := idtools.IDPair{UID: os.Geteuid(), GID: os.Getegid()}
+	r, err := New(rootDir, rootIDs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vol, err := r.Create("db", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(vol.Path(), "data"), []byte("initial"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	snap, err := vol.(volume.SnapshotVolume).Snapshot("db-initial")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Status()["SnapshotOf"] != "db" {
+		t.Fatalf("expected a snapshot of db, got %v", snap.Status())
+	}
+	if _, err := vol.(volume.SnapshotVolume).Snapshot("db-initial"); !errdefs.IsConflict(err) {
+		t.Fatalf("expected a conflict error, got %v", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(vol.Path(), "data"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	clone, err := snap.(volume.SnapshotVolume).Clone("db-reset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clone.Status() != nil {
+		t.Fatalf("expected a clone not to be a snapshot, got %v", clone.Status())
+	}
+	b, err := ioutil.ReadFile(filepath.Join(clone.Path(), "data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "initial" {
+		t.Fatalf("expected the clone to have the data of the snapshot, got %q", b)
+	}
+
+	// a copy interrupted by a restart is removed
+	if err := os.MkdirAll(filepath.Join(rootDir, volumesPathName, ".db-copy-123", VolumeDataPathName), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err = New(rootDir, rootIDs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l, _ := r.List(); len(l) != 3 {
+		t.Fatalf("expected 3 volumes, got %v", l)
+	}
+	v, err := r.Get("db-initial")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Status()["SnapshotOf"] != "db" {
+		t.Fatalf("expected a snapshot of db after restart, got %v", v.Status())
+	}
+	if _, err := os.Stat(filepath.Join(rootDir, volumesPathName, ".db-copy-123")); !os.IsNotExist(err) {
+		t.Fatalf("expected interrupted copy to be removed, got %v", err)
+	}
+	if err := r.Remove(v); err != nil {
+		t.Fatal(err)
+	}
+}
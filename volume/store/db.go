@@ -9,7 +9,10 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-var volumeBucketName = []byte("volumes")
+var (
+	volumeBucketName = []byte("volumes")
+	usageBucketName  = []byte("usage")
+)
 
 type volumeMetadata struct {
 	Name    string
@@ -61,6 +64,9 @@ func getMeta(tx *bolt.Tx, name string, meta *volumeMetadata) error {
 
 func (s *VolumeStore) removeMeta(name string) error {
 	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := removeUsage(tx, name); err != nil {
+			return err
+		}
 		return removeMeta(tx, name)
 	})
 }
@@ -93,3 +99,45 @@ func listMeta(tx *bolt.Tx) []volumeMetadata {
 	})
 	return ls
 }
+
+func setUsage(tx *bolt.Tx, name string, usage Usage) error {
+	usageJSON, err := json.Marshal(usage)
+	if err != nil {
+		return err
+	}
+	b, err := tx.CreateBucketIfNotExists(usageBucketName)
+	if err != nil {
+		return errors.Wrap(err, "error creating volume usage bucket")
+	}
+	return errors.Wrap(b.Put([]byte(name), usageJSON), "error setting volume usage")
+}
+
+func removeUsage(tx *bolt.Tx, name string) error {
+	b := tx.Bucket(usageBucketName)
+	if b == nil {
+		return nil
+	}
+	return errors.Wrap(b.Delete([]byte(name)), "error removing volume usage")
+}
+
+// listUsage is used during restore to get the usage of the volumes from the
+// on-disk database.
+// Any errors that occur are only logged.
+func listUsage(tx *bolt.Tx) map[string]Usage {
+	usage := make(map[string]Usage)
+	b := tx.Bucket(usageBucketName)
+	if b == nil {
+		return usage
+	}
+	b.ForEach(func(k, v []byte) error {
+		var u Usage
+		if err := json.Unmarshal(v, &u); err != nil {
+			// Just log the error
+			logrus.Errorf("Error while reading volume usage for volume %q: %v", string(k), err)
+			return nil
+		}
+		usage[string(k)] = u
+		return nil
+	})
+	return usage
+}
@@ -15,9 +15,13 @@ import (
 // It does not probe the available drivers to find anything that may have been added
 // out of band.
 func (s *VolumeStore) restore() {
-	var ls []volumeMetadata
+	var (
+		ls    []volumeMetadata
+		usage map[string]Usage
+	)
 	s.db.View(func(tx *bolt.Tx) error {
 		ls = listMeta(tx)
+		usage = listUsage(tx)
 		return nil
 	})
 
@@ -65,6 +69,9 @@ func (s *VolumeStore) restore() {
 			s.options[v.Name()] = meta.Options
 			s.labels[v.Name()] = meta.Labels
 			s.names[v.Name()] = v
+			if u, ok := usage[v.Name()]; ok {
+				s.usage[v.Name()] = u
+			}
 			s.globalLock.Unlock()
 		}(meta)
 	}
@@ -73,6 +80,9 @@ func (s *VolumeStore) restore() {
 	close(chRemove)
 	s.db.Update(func(tx *bolt.Tx) error {
 		for meta := range chRemove {
+			if err := removeUsage(tx, meta.Name); err != nil {
+				logrus.WithField("volume", meta.Name).Warnf("Error removing stale usage from volume db: %v", err)
+			}
 			if err := removeMeta(tx, meta.Name); err != nil {
 				logrus.WithField("volume", meta.Name).Warnf("Error removing stale entry from volume db: %v", err)
 			}
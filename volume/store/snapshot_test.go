@@ -0,0 +1,59 @@
+package store // import "github.com/ellcrys/docker/volume/store"
+
+import (
+	"io/ioutil"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/ellcrys/docker/errdefs"
+	"github.com/ellcrys/docker/pkg/idtools"
+	"github.com/ellcrys/docker/volume"
+	"github.com/ellcrys/docker/volume/local"
+	volumetestutils "github.com/ellcrys/docker/volume/testutils"
+	"github.com/gotestyourself/gotestyourself/assert"
+	is "github.com/gotestyourself/gotestyourself/assert/cmp"
+	"github.com/gotestyourself/gotestyourself/skip"
+)
+
+func TestSnapshot(t *testing.T) {
+	skip.If(t, runtime.GOOS != "linux", "volume snapshots are only supported on linux")
+	t.Parallel()
+
+	s, cleanup := setupTest(t)
+	defer cleanup()
+
+	dir, err := ioutil.TempDir("", "test-snapshot")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	r, err := local.New(dir, idtools.IDPair{UID: os.Geteuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+	s.drivers.Register(r, r.Name())
+
+	_, err = s.Create("db", "local", nil, map[string]string{"app": "db"})
+	assert.NilError(t, err)
+
+	snap, err := s.Snapshot("db-initial", "db", map[string]string{"snapshot": "initial"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(snap.DriverName(), "local"))
+	assert.Check(t, is.DeepEqual(snap.(volume.DetailedVolume).Labels(), map[string]string{"snapshot": "initial"}))
+	assert.Check(t, is.Equal(snap.Status()["SnapshotOf"], "db"))
+
+	_, err = s.Clone("db-initial", "db", nil)
+	assert.Check(t, IsNameConflict(err), err)
+
+	clone, err := s.Clone("db-reset", "db-initial", nil)
+	assert.NilError(t, err)
+	v, err := s.Get("db-reset")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(v.Path(), clone.Path()))
+
+	_, err = s.Clone("other", "missing", nil)
+	assert.Check(t, IsNotExist(err), err)
+
+	s.drivers.Register(volumetestutils.NewFakeDriver("fake"), "fake")
+	_, err = s.Create("fake1", "fake", nil, nil)
+	assert.NilError(t, err)
+	_, err = s.Snapshot("fake2", "fake1", nil)
+	assert.Check(t, errdefs.IsNotImplemented(err), err)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/boltdb/bolt"
+	"github.com/ellcrys/docker/errdefs"
 	"github.com/ellcrys/docker/pkg/locker"
 	"github.com/ellcrys/docker/volume"
 	"github.com/ellcrys/docker/volume/drivers"
@@ -27,6 +28,7 @@ type volumeWrapper struct {
 	labels  map[string]string
 	scope   string
 	options map[string]string
+	store   *VolumeStore
 }
 
 func (v volumeWrapper) Options() map[string]string {
@@ -65,6 +67,32 @@ func (v volumeWrapper) CachedPath() string {
 	return v.Volume.Path()
 }
 
+// Mount mounts the volume, recording the time it was mounted in the store.
+func (v volumeWrapper) Mount(id string) (string, error) {
+	path, err := v.Volume.Mount(id)
+	if err == nil && v.store != nil {
+		v.store.setMounted(v.Name())
+	}
+	return path, err
+}
+
+// Unmount unmounts the volume, recording in the store that its usage must be
+// measured again.
+func (v volumeWrapper) Unmount(id string) error {
+	err := v.Volume.Unmount(id)
+	if v.store != nil {
+		v.store.setStale(v.Name())
+	}
+	return err
+}
+
+func (v volumeWrapper) Usage() (int64, int64, error) {
+	if vv, ok := v.Volume.(volume.UsageVolume); ok {
+		return vv.Usage()
+	}
+	return 0, 0, errdefs.NotImplemented(errors.Errorf("volume driver %s does not report usage", v.DriverName()))
+}
+
 // New initializes a VolumeStore to keep
 // reference counting of volumes in the system.
 func New(rootPath string, drivers *drivers.Store) (*VolumeStore, error) {
@@ -74,6 +102,7 @@ func New(rootPath string, drivers *drivers.Store) (*VolumeStore, error) {
 		refs:    make(map[string]map[string]struct{}),
 		labels:  make(map[string]map[string]string),
 		options: make(map[string]map[string]string),
+		usage:   make(map[string]Usage),
 		drivers: drivers,
 	}
 
@@ -97,6 +126,9 @@ func New(rootPath string, drivers *drivers.Store) (*VolumeStore, error) {
 			if _, err := tx.CreateBucketIfNotExists(volumeBucketName); err != nil {
 				return errors.Wrap(err, "error while setting up volume store metadata database")
 			}
+			if _, err := tx.CreateBucketIfNotExists(usageBucketName); err != nil {
+				return errors.Wrap(err, "error while setting up volume store metadata database")
+			}
 			return nil
 		}); err != nil {
 			return nil, err
@@ -170,6 +202,7 @@ func (s *VolumeStore) Purge(name string) {
 	delete(s.refs, name)
 	delete(s.labels, name)
 	delete(s.options, name)
+	delete(s.usage, name)
 	s.globalLock.Unlock()
 }
 
@@ -190,7 +223,11 @@ type VolumeStore struct {
 	labels map[string]map[string]string
 	// options stores volume options for each volume
 	options map[string]map[string]string
-	db      *bolt.DB
+	// usage stores the measured usage and last mount time of each volume
+	usage map[string]Usage
+	db    *bolt.DB
+	// stopUsageMonitor stops the usage monitor, if started
+	stopUsageMonitor func()
 }
 
 // List proxies to all registered volume drivers to get the full list of volumes
@@ -250,7 +287,7 @@ func (s *VolumeStore) list() ([]volume.Volume, []string, error) {
 			}
 			for i, v := range vs {
 				s.globalLock.RLock()
-				vs[i] = volumeWrapper{v, s.labels[v.Name()], d.Scope(), s.options[v.Name()]}
+				vs[i] = volumeWrapper{v, s.labels[v.Name()], d.Scope(), s.options[v.Name()], s}
 				s.globalLock.RUnlock()
 			}
 
@@ -403,7 +440,7 @@ func (s *VolumeStore) create(name, driverName string, opts, labels map[string]st
 		// there is an existing volume, if we already have this stored locally, return it.
 		// TODO: there could be some inconsistent details such as labels here
 		if vv, _ := s.getNamed(v.Name()); vv != nil {
-			return vv, nil
+			return s.wrap(vv), nil
 		}
 	}
 
@@ -434,6 +471,13 @@ func (s *VolumeStore) create(name, driverName string, opts, labels map[string]st
 		}
 	}
 
+	return s.register(name, vd, v, opts, labels)
+}
+
+// register stores the labels and options of the volume v created by the
+// driver vd. It is expected that callers of this function hold any necessary
+// locks.
+func (s *VolumeStore) register(name string, vd volume.Driver, v volume.Volume, opts, labels map[string]string) (volume.Volume, error) {
 	s.globalLock.Lock()
 	s.labels[name] = labels
 	s.options[name] = opts
@@ -450,7 +494,67 @@ func (s *VolumeStore) create(name, driverName string, opts, labels map[string]st
 	if err := s.setMeta(name, metadata); err != nil {
 		return nil, err
 	}
-	return volumeWrapper{v, labels, vd.Scope(), opts}, nil
+	return volumeWrapper{v, labels, vd.Scope(), opts, s}, nil
+}
+
+// Snapshot creates a volume with the given name and labels from a snapshot
+// of the volume named from, taken by its driver. The driver must support
+// snapshots.
+func (s *VolumeStore) Snapshot(name, from string, labels map[string]string) (volume.Volume, error) {
+	return s.copyVolume(name, from, "snapshot", labels, volume.SnapshotVolume.Snapshot)
+}
+
+// Clone creates a volume with the given name and labels from a copy of the
+// volume named from, made by its driver. The driver must support snapshots.
+func (s *VolumeStore) Clone(name, from string, labels map[string]string) (volume.Volume, error) {
+	return s.copyVolume(name, from, "clone", labels, volume.SnapshotVolume.Clone)
+}
+
+func (s *VolumeStore) copyVolume(name, from, op string, labels map[string]string, copyFn func(volume.SnapshotVolume, string) (volume.Volume, error)) (volume.Volume, error) {
+	src, err := s.Get(from)
+	if err != nil {
+		return nil, err
+	}
+	sv, ok := unwrapVolume(src).(volume.SnapshotVolume)
+	if !ok {
+		return nil, &OpErr{Err: errdefs.NotImplemented(errors.Errorf("volume driver %s does not support snapshots", src.DriverName())), Name: src.Name(), Op: op}
+	}
+
+	name = normalizeVolumeName(name)
+	s.locks.Lock(name)
+	defer s.locks.Unlock(name)
+
+	parser := volumemounts.NewParser(runtime.GOOS)
+	if err := parser.ValidateVolumeName(name); err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: op}
+	}
+	v, err := s.checkConflict(name, "")
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: op}
+	}
+	if v != nil {
+		return nil, &OpErr{Err: errors.Wrapf(errNameConflict, "volume '%s' already exists", name), Name: name, Op: op}
+	}
+
+	vd, err := s.drivers.CreateDriver(src.DriverName())
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: op}
+	}
+	logrus.Debugf("Registering new volume reference: driver %q, name %q, %s of %q", vd.Name(), name, op, src.Name())
+	v, err = copyFn(sv, name)
+	if err != nil {
+		if _, err := s.drivers.ReleaseDriver(src.DriverName()); err != nil {
+			logrus.WithError(err).WithField("driver", src.DriverName()).Error("Error releasing reference to volume driver")
+		}
+		return nil, &OpErr{Err: err, Name: name, Op: op}
+	}
+
+	v, err = s.register(name, vd, v, nil, labels)
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: op}
+	}
+	s.setNamed(v, "")
+	return v, nil
 }
 
 // GetWithRef gets a volume with the given name from the passed in driver and stores the ref
@@ -478,7 +582,7 @@ func (s *VolumeStore) GetWithRef(name, driverName, ref string) (volume.Volume, e
 
 	s.globalLock.RLock()
 	defer s.globalLock.RUnlock()
-	return volumeWrapper{v, s.labels[name], vd.Scope(), s.options[name]}, nil
+	return volumeWrapper{v, s.labels[name], vd.Scope(), s.options[name], s}, nil
 }
 
 // Get looks if a volume with the given name exists and returns it if so
@@ -533,7 +637,7 @@ func (s *VolumeStore) getVolume(name string) (volume.Volume, error) {
 		if err == nil {
 			scope = vd.Scope()
 		}
-		return volumeWrapper{vol, meta.Labels, scope, meta.Options}, nil
+		return volumeWrapper{vol, meta.Labels, scope, meta.Options, s}, nil
 	}
 
 	logrus.Debugf("Probing all drivers for volume with name: %s", name)
@@ -551,7 +655,7 @@ func (s *VolumeStore) getVolume(name string) (volume.Volume, error) {
 		if err := s.setMeta(name, meta); err != nil {
 			return nil, err
 		}
-		return volumeWrapper{v, meta.Labels, d.Scope(), meta.Options}, nil
+		return volumeWrapper{v, meta.Labels, d.Scope(), meta.Options, s}, nil
 	}
 	return nil, errNoSuchVolume
 }
@@ -653,7 +757,7 @@ func (s *VolumeStore) FilterByDriver(name string) ([]volume.Volume, error) {
 		for key, value := range s.options[v.Name()] {
 			options[key] = value
 		}
-		ls[i] = volumeWrapper{v, s.labels[v.Name()], vd.Scope(), options}
+		ls[i] = volumeWrapper{v, s.labels[v.Name()], vd.Scope(), options, s}
 		s.globalLock.RUnlock()
 	}
 	return ls, nil
@@ -685,6 +789,21 @@ func (s *VolumeStore) filter(vols []volume.Volume, f filterFunc) []volume.Volume
 	return ls
 }
 
+// wrap returns the volume v with the labels, options and scope known to the
+// store, so that its mounts are tracked by the store.
+func (s *VolumeStore) wrap(v volume.Volume) volume.Volume {
+	if _, ok := v.(volumeWrapper); ok {
+		return v
+	}
+	var scope string
+	if vd, err := s.drivers.GetDriver(v.DriverName()); err == nil {
+		scope = vd.Scope()
+	}
+	s.globalLock.RLock()
+	defer s.globalLock.RUnlock()
+	return volumeWrapper{v, s.labels[v.Name()], scope, s.options[v.Name()], s}
+}
+
 func unwrapVolume(v volume.Volume) volume.Volume {
 	if vol, ok := v.(volumeWrapper); ok {
 		return vol.Volume
@@ -696,6 +815,9 @@ func unwrapVolume(v volume.Volume) volume.Volume {
 // Shutdown releases all resources used by the volume store
 // It does not make any changes to volumes, drivers, etc.
 func (s *VolumeStore) Shutdown() error {
+	if s.stopUsageMonitor != nil {
+		s.stopUsageMonitor()
+	}
 	return s.db.Close()
 }
 
@@ -13,6 +13,7 @@ import (
 	volumedrivers "github.com/ellcrys/docker/volume/drivers"
 	volumetestutils "github.com/ellcrys/docker/volume/testutils"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/gotestyourself/gotestyourself/assert"
 	is "github.com/gotestyourself/gotestyourself/assert/cmp"
 )
@@ -356,7 +357,7 @@ func TestGetWithRef(t *testing.T) {
 	assert.NilError(t, err)
 }
 
-var cmpVolume = cmp.AllowUnexported(volumetestutils.FakeVolume{}, volumeWrapper{})
+var cmpVolume = cmp.Options{cmp.AllowUnexported(volumetestutils.FakeVolume{}, volumeWrapper{}), cmpopts.IgnoreTypes(&VolumeStore{})}
 
 func setupTest(t *testing.T) (*VolumeStore, func()) {
 	t.Helper()
@@ -0,0 +1,236 @@
+package store // import "github.com/ellcrys/docker/volume/store"
+
+import (
+	"context"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/ellcrys/docker/errdefs"
+	"github.com/ellcrys/docker/pkg/directory"
+	"github.com/ellcrys/docker/volume"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Usage is the disk usage of a volume measured by the store, along with the
+// last time the volume was mounted.
+type Usage struct {
+	// Size is the disk space used by the volume, in bytes.
+	Size int64
+	// Inodes is the number of inodes used by the volume, -1 if the volume
+	// driver only reports its size.
+	Inodes int64
+	// Limit is the maximum disk space the volume can use, in bytes, 0 if the
+	// volume is not limited.
+	Limit int64
+	// MeasuredAt is the time the usage of the volume was measured, zero if
+	// the volume was not measured.
+	MeasuredAt time.Time
+	// LastMounted is the last time the volume was mounted, zero if the
+	// volume was not mounted since the store tracks it.
+	LastMounted time.Time
+
+	// stale is set when the volume is unmounted after it was measured, and
+	// may have changed since.
+	stale bool
+}
+
+// GetUsage returns the usage of the volume with the given name, as last
+// measured by the store, without measuring it.
+func (s *VolumeStore) GetUsage(name string) (Usage, bool) {
+	s.globalLock.RLock()
+	defer s.globalLock.RUnlock()
+	u, ok := s.usage[name]
+	return u, ok && !u.MeasuredAt.IsZero()
+}
+
+// Usage returns the usage of the volume v. The usage is measured if it was
+// not yet, or if the volume was unmounted since it was last measured, so that
+// only the volumes that may have changed are walked.
+func (s *VolumeStore) Usage(ctx context.Context, v volume.Volume) (Usage, error) {
+	s.globalLock.RLock()
+	u, ok := s.usage[v.Name()]
+	s.globalLock.RUnlock()
+	if ok && !u.stale && !u.MeasuredAt.IsZero() {
+		return u, nil
+	}
+
+	u, err := s.measure(ctx, v)
+	if err != nil {
+		return Usage{}, err
+	}
+	if err := s.saveUsage(v.Name()); err != nil {
+		logrus.WithError(err).WithField("volume", v.Name()).Warn("Error persisting volume usage")
+	}
+	return u, nil
+}
+
+// measure measures the usage of the volume v, and caches it if the volume is
+// known to the store.
+func (s *VolumeStore) measure(ctx context.Context, v volume.Volume) (Usage, error) {
+	name := v.Name()
+	s.globalLock.RLock()
+	opts := s.options[name]
+	s.globalLock.RUnlock()
+
+	var (
+		m        Usage
+		measured bool
+	)
+	if uv, ok := v.(volume.UsageVolume); ok {
+		if size, limit, err := uv.Usage(); err == nil {
+			m = Usage{Size: size, Inodes: -1, Limit: limit}
+			measured = true
+		}
+	}
+	if !measured {
+		if v.DriverName() != volume.DefaultDriverName || hasMountOptions(opts) {
+			return Usage{}, errdefs.NotImplemented(errors.Errorf("usage of volume %s cannot be measured", name))
+		}
+		size, inodes, err := directory.Usage(ctx, v.Path())
+		if err != nil {
+			return Usage{}, errors.Wrapf(err, "error measuring usage of volume %s", name)
+		}
+		m = Usage{Size: size, Inodes: inodes}
+	}
+	m.MeasuredAt = time.Now()
+
+	s.globalLock.Lock()
+	defer s.globalLock.Unlock()
+	if _, exists := s.names[name]; !exists {
+		return m, nil
+	}
+	m.LastMounted = s.usage[name].LastMounted
+	s.usage[name] = m
+	return m, nil
+}
+
+// updateUsage measures the usage of all the volumes of the local driver.
+func (s *VolumeStore) updateUsage(ctx context.Context) error {
+	vols, err := s.FilterByDriver(volume.DefaultDriverName)
+	if err != nil {
+		return err
+	}
+	var measured []string
+	for _, v := range vols {
+		if _, err := s.measure(ctx, v); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if !errdefs.IsNotImplemented(err) {
+				logrus.WithError(err).WithField("volume", v.Name()).Debug("Error measuring volume usage")
+			}
+			continue
+		}
+		measured = append(measured, v.Name())
+	}
+	return s.saveUsage(measured...)
+}
+
+// saveUsage persists the cached usage of the volumes with the given names.
+func (s *VolumeStore) saveUsage(names ...string) error {
+	s.globalLock.RLock()
+	usage := make(map[string]Usage, len(names))
+	for _, name := range names {
+		if u, ok := s.usage[name]; ok {
+			usage[name] = u
+		}
+	}
+	s.globalLock.RUnlock()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for name, u := range usage {
+			if err := setUsage(tx, name, u); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// StartUsageMonitor measures the usage of the volumes of the local driver
+// every interval in the background, until the store is shut down.
+func (s *VolumeStore) StartUsageMonitor(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	s.stopUsageMonitor = func() {
+		cancel()
+		<-done
+	}
+
+	go func() {
+		defer close(done)
+		for {
+			if err := s.updateUsage(ctx); err != nil && ctx.Err() == nil {
+				logrus.WithError(err).Warn("Error measuring volumes usage")
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+}
+
+// setMounted records that the volume with the given name was mounted.
+func (s *VolumeStore) setMounted(name string) {
+	s.globalLock.Lock()
+	u := s.usage[name]
+	u.LastMounted = time.Now()
+	s.usage[name] = u
+	s.globalLock.Unlock()
+
+	if err := s.saveUsage(name); err != nil {
+		logrus.WithError(err).WithField("volume", name).Warn("Error persisting volume last mount time")
+	}
+}
+
+// setStale records that the volume with the given name was unmounted, and
+// must be measured again.
+func (s *VolumeStore) setStale(name string) {
+	s.globalLock.Lock()
+	if u, ok := s.usage[name]; ok {
+		u.stale = true
+		s.usage[name] = u
+	}
+	s.globalLock.Unlock()
+}
+
+// FilterByUnusedSince returns the volumes that are not in use, and were last
+// mounted before since. Volumes that were not mounted since the store tracks
+// them are returned if they were created before since.
+func (s *VolumeStore) FilterByUnusedSince(vols []volume.Volume, since time.Time) []volume.Volume {
+	return s.filter(vols, func(v volume.Volume) bool {
+		s.locks.Lock(v.Name())
+		hasRef := s.hasRef(v.Name())
+		s.locks.Unlock(v.Name())
+		if hasRef {
+			return false
+		}
+
+		s.globalLock.RLock()
+		lastUsed := s.usage[v.Name()].LastMounted
+		s.globalLock.RUnlock()
+		if lastUsed.IsZero() {
+			createdAt, err := v.CreatedAt()
+			if err != nil {
+				return false
+			}
+			lastUsed = createdAt
+		}
+		return lastUsed.Before(since)
+	})
+}
+
+// hasMountOptions returns true if the options of a volume of the local driver
+// mount a filesystem at the volume path, which could be slow to walk, rather
+// than only limiting its size.
+func hasMountOptions(opts map[string]string) bool {
+	for k := range opts {
+		if k != "size" {
+			return true
+		}
+	}
+	return false
+}
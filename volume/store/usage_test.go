@@ -0,0 +1,113 @@
+package store // import "github.com/ellcrys/docker/volume/store"
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ellcrys/docker/pkg/idtools"
+	"github.com/ellcrys/docker/volume"
+	volumedrivers "github.com/ellcrys/docker/volume/drivers"
+	"github.com/ellcrys/docker/volume/local"
+	"github.com/gotestyourself/gotestyourself/assert"
+	is "github.com/gotestyourself/gotestyourself/assert/cmp"
+)
+
+func TestUsage(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "test-usage")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	newStore := func() *VolumeStore {
+		r, err := local.New(dir, idtools.IDPair{UID: os.Geteuid(), GID: os.Getegid()})
+		assert.NilError(t, err)
+		drivers := volumedrivers.NewStore(nil)
+		drivers.Register(r, r.Name())
+		s, err := New(dir, drivers)
+		assert.NilError(t, err)
+		return s
+	}
+	s := newStore()
+
+	v, err := s.Create("data", "local", nil, nil)
+	assert.NilError(t, err)
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(v.Path(), "file"), make([]byte, 10), 0644))
+
+	u, err := s.Usage(context.Background(), v)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(u.Size, int64(10)))
+	assert.Check(t, is.Equal(u.Inodes, int64(2)))
+	assert.Check(t, u.LastMounted.IsZero())
+
+	// the usage is not measured again until the volume is unmounted
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(v.Path(), "file2"), make([]byte, 5), 0644))
+	u, err = s.Usage(context.Background(), v)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(u.Size, int64(10)))
+
+	_, err = v.Mount("test")
+	assert.NilError(t, err)
+	assert.NilError(t, v.Unmount("test"))
+	u, err = s.Usage(context.Background(), v)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(u.Size, int64(15)))
+	assert.Check(t, is.Equal(u.Inodes, int64(3)))
+	assert.Check(t, !u.LastMounted.IsZero())
+
+	// the usage and last mount time are restored from the database
+	assert.NilError(t, s.Shutdown())
+	s = newStore()
+	defer s.Shutdown()
+	restored, ok := s.GetUsage("data")
+	assert.Assert(t, ok)
+	assert.Check(t, is.Equal(restored.Size, int64(15)))
+	assert.Check(t, restored.LastMounted.Equal(u.LastMounted))
+
+	v, err = s.Get("data")
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(s.FilterByUnusedSince([]volume.Volume{v}, u.LastMounted), 0))
+	assert.Check(t, is.Len(s.FilterByUnusedSince([]volume.Volume{v}, time.Now()), 1))
+
+	_, err = s.GetWithRef("data", "local", "container")
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(s.FilterByUnusedSince([]volume.Volume{v}, time.Now()), 0))
+	s.Dereference(v, "container")
+
+	assert.NilError(t, s.Remove(v))
+	_, ok = s.GetUsage("data")
+	assert.Check(t, !ok)
+}
+
+func TestUpdateUsage(t *testing.T) {
+	t.Parallel()
+
+	s, cleanup := setupTest(t)
+	defer cleanup()
+
+	dir, err := ioutil.TempDir("", "test-update-usage")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	r, err := local.New(dir, idtools.IDPair{UID: os.Geteuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+	s.drivers.Register(r, r.Name())
+
+	v, err := s.Create("data", "local", nil, nil)
+	assert.NilError(t, err)
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(v.Path(), "file"), make([]byte, 10), 0644))
+	_, err = s.Create("tmpfs", "local", map[string]string{"type": "tmpfs", "device": "tmpfs"}, nil)
+	assert.NilError(t, err)
+
+	assert.NilError(t, s.updateUsage(context.Background()))
+	u, ok := s.GetUsage("data")
+	assert.Assert(t, ok)
+	assert.Check(t, is.Equal(u.Size, int64(10)))
+
+	// volumes with mount options are not measured
+	_, ok = s.GetUsage("tmpfs")
+	assert.Check(t, !ok)
+}
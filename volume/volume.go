@@ -60,6 +60,28 @@ type Volume interface {
 	Status() map[string]interface{}
 }
 
+// SnapshotVolume is a Volume its driver can make copies of. It is optional
+// for drivers to implement.
+type SnapshotVolume interface {
+	Volume
+	// Snapshot creates a new volume with the given name, holding a point in
+	// time copy of the data of the volume. The new volume is recorded as a
+	// snapshot of the volume.
+	Snapshot(name string) (Volume, error)
+	// Clone creates a new volume with the given name, holding a copy of the
+	// data of the volume.
+	Clone(name string) (Volume, error)
+}
+
+// UsageVolume is a Volume that reports the disk space it uses. It is optional
+// for drivers to implement.
+type UsageVolume interface {
+	Volume
+	// Usage returns the disk space used by the volume and the maximum disk
+	// space it may use, in bytes.
+	Usage() (size int64, limit int64, err error)
+}
+
 // DetailedVolume wraps a Volume with user-defined labels, options, and cluster scope (e.g., `local` or `global`)
 type DetailedVolume interface {
 	Labels() map[string]string